@@ -62,6 +62,6 @@ func init() {
 	compressCmd.Flags().Float64VarP(&att, "attack", "a", 10.0, "Compression attack time in ms")
 	compressCmd.Flags().Float64VarP(&rel, "release", "R", 300.0, "Compression release time in ms")
 	compressCmd.Flags().Float64VarP(&knee, "knee", "k", -25.0, "Compression soft knee width in dB")
-	compressCmd.Flags().BoolP("makeup", "m", false, "Apply makeup gain")
+	compressCmd.Flags().BoolVarP(&makeup, "makeup", "m", false, "Apply makeup gain")
 	compressCmd.Flags().Float64VarP(&gain, "gain", "g", -1.0, "Makeup gain in dB")
 }
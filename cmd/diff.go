@@ -0,0 +1,93 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffGain     float64
+	diffOffset   float64
+	diffAlign    bool
+	maxShift     float64
+	tolerance    float64
+	residualFile string
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Null tests two tracks against each other.",
+	Long: `Subtracts the second track from the first and reports the largest difference,
+the RMS level of the residual and the first sample that differs. Tracks are compared
+relative to full scale, so files of different bit depths can be compared.
+The second track can be delayed and amplified first, or aligned automatically.
+Exits with status 1 when the tracks differ.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+		file2 := args[1]
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		track2 := dsp.NewWav()
+		track2.ReadFile(file2)
+
+		sr := float64(track1.SampleRate())
+		d, residual := track1.Diff(track2, int(diffOffset*sr), diffGain, diffAlign, int(maxShift*sr), tolerance)
+		if residualFile != "" {
+			residual.WriteFile(residualFile)
+		}
+
+		if jsonOutput {
+			printJSON(os.Stdout, d)
+		} else {
+			fmt.Printf("Comparing %s with %s\n", path.Base(file1), path.Base(file2))
+			fmt.Printf("Offset:          %d samples (%.4fs)\n", d.Offset, float64(d.Offset)/sr)
+			fmt.Printf("Gain:            %.2f dB\n", d.Gain)
+			fmt.Printf("Max difference:  %.2f dBFS\n", d.MaxAbsDBFS)
+			fmt.Printf("RMS difference:  %.2f dBFS\n", d.RMSDBFS)
+			if d.FirstSample >= 0 {
+				fmt.Printf("First difference at sample %d (%.4fs)\n", d.FirstSample, d.FirstTime)
+			} else if d.Identical {
+				fmt.Println("Tracks are identical.")
+			} else {
+				fmt.Println("Tracks match where they overlap.")
+			}
+		}
+		if !d.Identical {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().Float64VarP(&diffGain, "gain", "g", 0.0, "Gain in dB applied to the second track")
+	diffCmd.Flags().Float64VarP(&diffOffset, "offset", "d", 0.0, "Delay in seconds applied to the second track")
+	diffCmd.Flags().BoolVarP(&diffAlign, "align", "a", false, "Estimate gain and delay from the tracks")
+	diffCmd.Flags().Float64VarP(&maxShift, "max-shift", "m", 1.0, "Largest delay in seconds searched when aligning")
+	diffCmd.Flags().Float64VarP(&tolerance, "tolerance", "t", 0.0, "Largest difference as a fraction of full scale still counted as equal")
+	diffCmd.Flags().StringVarP(&residualFile, "residual", "r", "", "File to write the residual to")
+	diffCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Print as JSON")
+}
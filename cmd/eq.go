@@ -0,0 +1,154 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	eqBands    []string
+	eqPreset   string
+	eqPreamp   float64
+	eqSettings dsp.Preset
+)
+
+// parseBand parses a band given as type:frequency[:gain[:q]].
+func parseBand(s string) (dsp.Band, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) < 2 || len(fields) > 4 {
+		return dsp.Band{}, fmt.Errorf("invalid band %q, want type:frequency[:gain[:q]]", s)
+	}
+	b := dsp.Band{Type: fields[0]}
+	values := []*float64{&b.Frequency, &b.Gain, &b.Q}
+	for i, f := range fields[1:] {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return dsp.Band{}, fmt.Errorf("invalid band %q: %v", s, err)
+		}
+		*values[i] = v
+	}
+	return b, nil
+}
+
+// readPreset returns the preset in the given file, YAML when it ends in .yaml or .yml and JSON otherwise.
+func readPreset(file string) (dsp.Preset, error) {
+	var p dsp.Preset
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return p, err
+	}
+	unmarshal := json.Unmarshal
+	if ext := strings.ToLower(path.Ext(file)); ext == ".yaml" || ext == ".yml" {
+		unmarshal = yaml.Unmarshal
+	}
+	if err := unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("invalid preset %s: %v", file, err)
+	}
+	return p, nil
+}
+
+// eqPresetFromFlags returns the preset file, if any, with the preamp and bands from the flags added.
+// The bands are checked against the sample rate once the track is read.
+func eqPresetFromFlags(cmd *cobra.Command) (dsp.Preset, error) {
+	var p dsp.Preset
+	if eqPreset != "" {
+		var err error
+		if p, err = readPreset(eqPreset); err != nil {
+			return p, err
+		}
+	}
+	if cmd.Flags().Changed("preamp") {
+		p.Preamp = eqPreamp
+	}
+	for _, s := range eqBands {
+		b, err := parseBand(s)
+		if err != nil {
+			return p, err
+		}
+		p.Bands = append(p.Bands, b)
+	}
+	if len(p.Bands) == 0 {
+		return p, fmt.Errorf("requires at least one band, from --band or --preset")
+	}
+	return p, nil
+}
+
+// eqCmd represents the eq command
+var eqCmd = &cobra.Command{
+	Use:   "eq",
+	Short: "Applies a multi-band parametric EQ to a track.",
+	Long: `Runs a track through any number of Audio EQ Cookbook bands in a single pass,
+so the track is only quantized once. Each band is given as --band type:frequency[:gain[:q]],
+for example --band peak:1000:-3:1.4 --band highshelf:8000:2, or read from a JSON preset:
+
+  {"preamp": -2, "bands": [{"type": "lowshelf", "frequency": 120, "gain": 3, "slope": 1},
+                           {"type": "peak", "frequency": 2500, "gain": -4, "bandwidth": 1}]}
+
+or the same preset as YAML in a file ending in .yaml or .yml:
+
+  preamp: -2
+  bands:
+    - {type: lowshelf, frequency: 120, gain: 3, slope: 1}
+    - {type: peak, frequency: 2500, gain: -4, bandwidth: 1}
+
+Bands from --band follow those of the preset. Types are lpf, hpf, bpf, bpfskirt, notch, apf,
+peak, lowshelf and highshelf. The result is clipped to full scale, lower --preamp to avoid it.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		eqSettings, err = eqPresetFromFlags(cmd)
+		return err
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+		p := eqSettings
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		for _, b := range p.Bands {
+			cobra.CheckErr(b.Validate(track1.SampleRate()))
+		}
+		fmt.Printf("---------------\n%s details:\n", path.Base(file1))
+		track1.DumpHeader(false)
+
+		fmt.Printf("Equalizing with %d bands (preamp %gdB)...\n", len(p.Bands), p.Preamp)
+		for _, b := range p.Bands {
+			fmt.Printf("  %-9s %8g Hz %+6g dB  Q=%g bw=%g slope=%g\n", b.Type, b.Frequency, b.Gain, b.Q, b.Bandwidth, b.Slope)
+		}
+		track1.EQ(p)
+		track1.WriteFile(outFile)
+
+		fmt.Printf("Equalized into %s.\n", outFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(eqCmd)
+	eqCmd.Flags().StringArrayVarP(&eqBands, "band", "b", nil, "Band as type:frequency[:gain[:q]], repeatable")
+	eqCmd.Flags().StringVarP(&eqPreset, "preset", "p", "", "JSON or YAML preset file with preamp and bands")
+	eqCmd.Flags().Float64VarP(&eqPreamp, "preamp", "P", 0.0, "Gain in dB applied before the bands, overrides the preset")
+}
@@ -26,13 +26,23 @@ import (
 )
 
 var (
-	lh        int
-	freq      int
-	bandwidth int
+	lh         int
+	freq       float64
+	bandwidth  int
+	ripple     float64
+	poles      int
+	butterKind string
+	order      int
+	high       float64
+	bandQ      float64
+	octaves    float64
+	slope      float64
+	bandGain   float64
 )
 
 func isValidFilter(filter string) bool {
-	filters := []string{"avg", "biquad", "windowedsinc", "highpass"}
+	filters := []string{"avg", "biquad", "windowedsinc", "highpass", "cheb", "butter",
+		dsp.LPF, dsp.HPF, dsp.BPF, dsp.BPFSkirt, dsp.Notch, dsp.APF, dsp.PeakingEQ, dsp.LowShelf, dsp.HighShelf}
 	for _, v := range filters {
 		if filter == v {
 			return true
@@ -41,21 +51,61 @@ func isValidFilter(filter string) bool {
 	return false
 }
 
+// checkButterworth returns an error for Butterworth flags that cannot be designed at the sample rate.
+func checkButterworth(sampleRate int) error {
+	nyquist := float64(sampleRate) / 2
+	if order < 1 {
+		return fmt.Errorf("invalid Butterworth order: %d", order)
+	}
+	if freq <= 0 || freq >= nyquist {
+		return fmt.Errorf("cut off frequency %g Hz must be between 0 and %g Hz", freq, nyquist)
+	}
+	if (butterKind == dsp.Bandpass || butterKind == dsp.Bandstop) && (high <= freq || high >= nyquist) {
+		return fmt.Errorf("high edge %g Hz must be between %g and %g Hz", high, freq, nyquist)
+	}
+	return nil
+}
+
+// checkChebyshev returns an error for Chebyshev flags that cannot be designed at the sample rate.
+func checkChebyshev(sampleRate int) error {
+	if nyquist := float64(sampleRate) / 2; freq <= 0 || freq >= nyquist {
+		return fmt.Errorf("cut off frequency %g Hz must be between 0 and %g Hz", freq, nyquist)
+	}
+	if poles < 2 || poles > 20 || poles%2 != 0 {
+		return fmt.Errorf("invalid Chebyshev poles, must be even from 2 to 20: %d", poles)
+	}
+	if ripple < 0 || ripple > 29 {
+		return fmt.Errorf("invalid Chebyshev ripple, must be from 0 to 29 percent: %g", ripple)
+	}
+	return nil
+}
+
+// cookbookBand returns the Audio EQ Cookbook band of the named filter using the filter flags.
+func cookbookBand(filter string) dsp.Band {
+	return dsp.Band{Type: filter, Frequency: freq, Gain: bandGain, Q: bandQ, Bandwidth: octaves, Slope: slope}
+}
+
 // filterCmd represents the filter command
 var filterCmd = &cobra.Command{
 	Use:   "filter",
 	Short: "Apply various filters on a track.",
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			return errors.New("requires a filter to be selected (avg, biquad, windowedsinc, highpass)")
+			return errors.New("requires a filter to be selected (avg, biquad, windowedsinc, highpass, cheb, butter, lpf, hpf, bpf, bpfskirt, notch, apf, peak, lowshelf, highshelf)")
 		}
 		if len(args) < 2 {
 			return errors.New("requires an input file to be specfied")
 		}
-		if isValidFilter(args[0]) {
-			return nil
+		if !isValidFilter(args[0]) {
+			return fmt.Errorf("invalid filter specified: %s", args[0])
 		}
-		return fmt.Errorf("invalid filter specified: %s", args[0])
+		if !isValidFormat(butterKind, dsp.Lowpass, dsp.Highpass, dsp.Bandpass, dsp.Bandstop) {
+			return fmt.Errorf("invalid Butterworth type specified: %s", butterKind)
+		}
+		if order < 1 {
+			return fmt.Errorf("invalid Butterworth order: %d", order)
+		}
+		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		filter := args[0]
@@ -72,19 +122,31 @@ var filterCmd = &cobra.Command{
 			track1.RollingAvgLowpass(bandwidth)
 
 		case "windowedsinc":
-			fmt.Printf("Convolving using Windowed-Sinc (fc=%d, M=%d)...\n", freq, bandwidth)
-			track1.WindowedSinc(freq, bandwidth)
+			fmt.Printf("Convolving using Windowed-Sinc (fc=%d, M=%d)...\n", int(freq), bandwidth)
+			track1.WindowedSinc(int(freq), bandwidth)
 
 		case "biquad":
-			fmt.Printf("Convolving using Biquad (fc=%d, lh=%d)...\n", freq, lh)
-			track1.Biquad(freq, lh)
+			fmt.Printf("Convolving using Biquad (fc=%d, lh=%d)...\n", int(freq), lh)
+			track1.Biquad(int(freq), lh)
 
 		case "highpass":
 			fmt.Printf("Convolving using highpass...")
 			track1.Highpass()
 
 		case "cheb":
-			// track1.chebyshev()
+			cobra.CheckErr(checkChebyshev(track1.SampleRate()))
+			fmt.Printf("Convolving using Chebyshev (fc=%g, lh=%d, ripple=%f%%, poles=%d)...\n", freq, lh, ripple, poles)
+			track1.Chebyshev(freq, lh, ripple, poles)
+
+		case "butter":
+			cobra.CheckErr(checkButterworth(track1.SampleRate()))
+			fmt.Printf("Convolving using Butterworth (%s, fc=%g, high=%g, order=%d)...\n", butterKind, freq, high, order)
+			track1.Butterworth(butterKind, order, freq, high)
+
+		case dsp.LPF, dsp.HPF, dsp.BPF, dsp.BPFSkirt, dsp.Notch, dsp.APF, dsp.PeakingEQ, dsp.LowShelf, dsp.HighShelf:
+			fmt.Printf("Convolving using cookbook %s (fc=%g, gain=%gdB, Q=%g, bw=%g, slope=%g)...\n", filter, freq, bandGain, bandQ, octaves, slope)
+			cobra.CheckErr(cookbookBand(filter).Validate(track1.SampleRate()))
+			track1.Cookbook(cookbookBand(filter))
 
 		default:
 			fmt.Println("Please enter a valid filter for convolution")
@@ -97,6 +159,15 @@ var filterCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(filterCmd)
 	filterCmd.Flags().IntVarP(&lh, "lh", "l", 0, "Low pass: 0, High pass: 1")
-	filterCmd.Flags().IntVarP(&freq, "freq", "f", 5000, "Cut off frequency")
+	filterCmd.Flags().Float64VarP(&freq, "freq", "f", 5000, "Cut off frequency, the low edge for band filters")
 	filterCmd.Flags().IntVarP(&bandwidth, "bandwidth", "b", 20, "Roll off value for certain filters")
+	filterCmd.Flags().Float64VarP(&ripple, "ripple", "r", 0.5, "Passband ripple in percent for Chebyshev")
+	filterCmd.Flags().IntVarP(&poles, "poles", "p", 4, "Even number of poles for Chebyshev, 2 to 20")
+	filterCmd.Flags().StringVarP(&butterKind, "type", "t", dsp.Lowpass, "Butterworth type (lowpass, highpass, bandpass, bandstop)")
+	filterCmd.Flags().IntVarP(&order, "order", "O", 4, "Order of Butterworth, doubled for band filters")
+	filterCmd.Flags().Float64VarP(&high, "high", "H", 10000, "High edge of Butterworth band filters")
+	filterCmd.Flags().Float64VarP(&bandQ, "q", "q", 0.7071, "Q of cookbook filters")
+	filterCmd.Flags().Float64VarP(&octaves, "octaves", "w", 0, "Bandwidth in octaves of cookbook filters, overrides Q")
+	filterCmd.Flags().Float64VarP(&slope, "slope", "s", 0, "Slope of cookbook shelves, overrides Q")
+	filterCmd.Flags().Float64VarP(&bandGain, "gain", "G", 0, "Gain in dB of cookbook peaking and shelving filters")
 }
@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	jsonOutput bool
+)
+
+// fileInfo is a dsp.Info labelled with the file it was read from.
+type fileInfo struct {
+	File string `json:"file"`
+	dsp.Info
+}
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Prints header details and levels of tracks.",
+	Long:  `Prints header details, derived fields and per channel levels of one or more tracks.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var infos []fileInfo
+		for _, file := range args {
+			track := dsp.NewWav()
+			track.ReadFile(file)
+			info := track.Info()
+			if jsonOutput {
+				infos = append(infos, fileInfo{File: file, Info: info})
+				continue
+			}
+
+			fmt.Printf("---------------\n%s details:\n", path.Base(file))
+			track.DumpHeader(true)
+			for i, ch := range info.Channels {
+				fmt.Printf("Channel %d: peak %.2f dBFS, true peak %.2f dBTP, RMS %.2f dBFS\n", i+1, ch.PeakDBFS, ch.TruePeakDBTP, ch.RMSDBFS)
+			}
+		}
+		if jsonOutput {
+			printJSON(os.Stdout, infos)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+	infoCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Print as JSON")
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+// fileLoudness is a dsp.Loudness labelled with the file it was measured from.
+type fileLoudness struct {
+	File string `json:"file"`
+	dsp.Loudness
+}
+
+// loudnessCmd represents the loudness command
+var loudnessCmd = &cobra.Command{
+	Use:   "loudness",
+	Short: "Measures the loudness of tracks.",
+	Long: `Measures the ITU-R BS.1770-4 / EBU R128 loudness of one or more tracks.
+
+Reports integrated loudness, maximum momentary and short-term loudness in LUFS and loudness range in LU.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var results []fileLoudness
+		for _, file := range args {
+			track := dsp.NewWav()
+			track.ReadFile(file)
+			l := track.Loudness()
+			if jsonOutput {
+				results = append(results, fileLoudness{File: file, Loudness: l})
+				continue
+			}
+
+			fmt.Printf("---------------\n%s loudness:\n", path.Base(file))
+			fmt.Printf("%-16s %.1f LUFS\n", "Integrated:", l.Integrated)
+			fmt.Printf("%-16s %.1f LUFS\n", "Momentary max:", l.MomentaryMax)
+			fmt.Printf("%-16s %.1f LUFS\n", "Short-term max:", l.ShortTermMax)
+			fmt.Printf("%-16s %.1f LU\n", "Range:", l.Range)
+		}
+		if jsonOutput {
+			printJSON(os.Stdout, results)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loudnessCmd)
+	loudnessCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Print as JSON")
+}
@@ -24,12 +24,20 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	align       bool
+	mixMaxShift float64
+	phat        bool
+)
+
 // mixCmd represents the mix command
 var mixCmd = &cobra.Command{
 	Use:   "mix",
 	Short: "Mixes two tracks into one.",
-	Long:  `Mixes two tracks into one.`,
-	Args:  cobra.ExactArgs(2),
+	Long: `Mixes two tracks into one.
+With --align the second track is first shifted by its lag behind the first,
+found by cross-correlation within --max-shift seconds.`,
+	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		file1 := args[0]
 		file2 := args[1]
@@ -45,6 +53,16 @@ var mixCmd = &cobra.Command{
 		fmt.Printf("---------------\n%s details:\n", path.Base(file2))
 		track2.DumpHeader(false)
 
+		if track1.SampleRate() != track2.SampleRate() || track1.NumChannels() != track2.NumChannels() || track1.BitsPerSample() != track2.BitsPerSample() {
+			cobra.CheckErr(fmt.Errorf("%s and %s differ in sample rate, channels or bit depth", path.Base(file1), path.Base(file2)))
+		}
+
+		if align {
+			lag := track1.Lag(track2, int(mixMaxShift*float64(track1.SampleRate())), phat)
+			fmt.Printf("Shifting %s by %d samples (%.4fs)\n", path.Base(file2), -lag, float64(-lag)/float64(track1.SampleRate()))
+			track2.Shift(-lag)
+		}
+
 		newTrack := dsp.NewWav()
 		newTrack.Mix(track1, track2)
 		newTrack.WriteFile(outFile)
@@ -55,4 +73,7 @@ var mixCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(mixCmd)
+	mixCmd.Flags().BoolVarP(&align, "align", "a", false, "Align the second track to the first before mixing")
+	mixCmd.Flags().Float64VarP(&mixMaxShift, "max-shift", "m", 1.0, "Largest lag in seconds searched when aligning")
+	mixCmd.Flags().BoolVarP(&phat, "phat", "p", false, "Align with GCC-PHAT, more robust to reverb and colouring")
 }
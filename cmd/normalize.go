@@ -25,25 +25,93 @@ import (
 )
 
 var (
-	peak float64
+	peak    float64
+	lufs    float64
+	ceiling float64
+	limit   bool
+	dbtp    bool
+	mode    string
+	rmsDB   float64
+	crest   float64
+	silence float64
 )
 
+func isValidMode(mode string) bool {
+	modes := []string{"peak", "loudness", "rms", "crest"}
+	for _, v := range modes {
+		if mode == v {
+			return true
+		}
+	}
+	return false
+}
+
 // normalizeCmd represents the normalize command
 var normalizeCmd = &cobra.Command{
 	Use:   "normalize",
 	Short: "Normalizes the given tracks amplitude",
-	Long:  `Normalizes the given tracks amplitude`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Normalizes the given tracks amplitude
+
+Modes:
+  peak      Sample or true peak to --peak
+  loudness  Integrated loudness to --lufs with a --ceiling in dBTP
+  rms       RMS level to --rms, ignoring silence below --silence
+  crest     RMS level to --rms with peaks limited to --crest dB above it`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if !isValidMode(mode) {
+			return fmt.Errorf("invalid mode specified: %s", mode)
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		file1 := args[0]
-		fmt.Printf("Noramlizing %s to %f dBFS\n", path.Base(file1), peak)
+		if cmd.Flags().Changed("lufs") && !cmd.Flags().Changed("mode") {
+			mode = "loudness"
+		}
+		switch mode {
+		case "loudness":
+			fmt.Printf("Normalizing %s to %f LUFS with a %f dBTP ceiling\n", path.Base(file1), lufs, ceiling)
+		case "rms":
+			fmt.Printf("Normalizing %s to %f dBFS RMS\n", path.Base(file1), rmsDB)
+		case "crest":
+			fmt.Printf("Normalizing %s to %f dBFS RMS with at most %f dB crest factor\n", path.Base(file1), rmsDB, crest)
+		default:
+			if dbtp {
+				fmt.Printf("Normalizing %s to %f dBTP\n", path.Base(file1), peak)
+			} else {
+				fmt.Printf("Normalizing %s to %f dBFS\n", path.Base(file1), peak)
+			}
+		}
 
 		track1 := dsp.NewWav()
 		track1.ReadFile(file1)
 		fmt.Printf("---------------\n%s details:\n", path.Base(file1))
 		track1.DumpHeader(false)
 
-		track1.Normalize(peak)
+		switch mode {
+		case "loudness":
+			gain, conflict := track1.NormalizeLoudness(lufs, ceiling, limit)
+			if conflict && limit {
+				fmt.Printf("Target exceeds the ceiling, limiting peaks to %f dBTP\n", ceiling)
+			} else if conflict {
+				fmt.Printf("Target exceeds the ceiling, gain reduced to %f dB\n", gain)
+			} else {
+				fmt.Printf("Applied %f dB of gain\n", gain)
+			}
+		case "rms":
+			track1.NormalizeRMS(rmsDB, silence)
+		case "crest":
+			track1.NormalizeCrest(rmsDB, crest, silence)
+		default:
+			if dbtp {
+				track1.NormalizeTruePeak(peak)
+			} else {
+				track1.Normalize(peak)
+			}
+		}
 		track1.WriteFile(outFile)
 
 		fmt.Printf("Normalized into %s.\n", outFile)
@@ -52,5 +120,13 @@ var normalizeCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(normalizeCmd)
+	normalizeCmd.Flags().StringVarP(&mode, "mode", "m", "peak", "Normalization mode (peak, loudness, rms, crest)")
 	normalizeCmd.Flags().Float64VarP(&peak, "peak", "p", -1.0, "Desired peak in dB")
+	normalizeCmd.Flags().BoolVarP(&dbtp, "true-peak", "t", false, "Treat the desired peak as 4x oversampled true peak in dBTP")
+	normalizeCmd.Flags().Float64VarP(&lufs, "lufs", "L", -23.0, "Desired integrated loudness in LUFS")
+	normalizeCmd.Flags().Float64VarP(&ceiling, "ceiling", "c", -1.0, "True peak ceiling in dBTP when normalizing loudness")
+	normalizeCmd.Flags().Float64VarP(&rmsDB, "rms", "r", -20.0, "Desired RMS level in dBFS")
+	normalizeCmd.Flags().Float64VarP(&crest, "crest", "C", 12.0, "Largest crest factor in dB for crest mode")
+	normalizeCmd.Flags().Float64VarP(&silence, "silence", "s", -60.0, "Level in dBFS below which windows are left out of RMS")
+	normalizeCmd.Flags().BoolVarP(&limit, "limit", "l", false, "Limit peaks above the ceiling instead of reducing gain")
 }
@@ -0,0 +1,69 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	onsetSize  int
+	onsetHop   int
+	onsetDelta float64
+	minGap     float64
+)
+
+// onsetsCmd represents the onsets command
+var onsetsCmd = &cobra.Command{
+	Use:   "onsets",
+	Short: "Detects onsets in a track.",
+	Long: `Detects onsets in a track from peaks of its spectral flux.
+
+Prints one onset time in seconds per line, to stdout unless --out is given.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if onsetSize < 2 || onsetHop < 1 {
+			return fmt.Errorf("invalid frame size or hop: %d, %d", onsetSize, onsetHop)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+
+		out := openOutput(cmd)
+		defer out.Close()
+		for _, t := range track1.Onsets(onsetSize, onsetHop, onsetDelta, minGap) {
+			fmt.Fprintf(out, "%.4f\n", t)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(onsetsCmd)
+	onsetsCmd.Flags().IntVarP(&onsetSize, "size", "n", 2048, "Frame size in samples")
+	onsetsCmd.Flags().IntVarP(&onsetHop, "hop", "H", 512, "Hop between frames in samples")
+	onsetsCmd.Flags().Float64VarP(&onsetDelta, "delta", "d", 0.5, "Height above the local mean an onset needs, in standard deviations")
+	onsetsCmd.Flags().Float64VarP(&minGap, "gap", "g", 0.05, "Shortest time between onsets in seconds")
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// nopCloser is stdout as an io.WriteCloser whose Close leaves stdout open.
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// openOutput returns the file given with --out, or stdout when it was not set.
+// Reports default to stdout rather than the ./out.wav used for tracks.
+// Closing the result only closes a file it opened.
+func openOutput(cmd *cobra.Command) io.WriteCloser {
+	if !cmd.Flags().Changed("out") {
+		return nopCloser{os.Stdout}
+	}
+	f, err := os.Create(outFile)
+	cobra.CheckErr(err)
+	return f
+}
+
+// imageOutput returns the file given with --out, or def when it was not set.
+// Rendered images should not default to the ./out.wav used for tracks.
+func imageOutput(cmd *cobra.Command, def string) string {
+	if !cmd.Flags().Changed("out") {
+		return def
+	}
+	return outFile
+}
+
+// printJSON writes v to w as indented JSON.
+func printJSON(w io.Writer, v interface{}) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	cobra.CheckErr(enc.Encode(v))
+}
+
+// printCSV writes a header and columns of equal length to w as CSV.
+func printCSV(w io.Writer, header []string, columns ...[]float64) {
+	out := csv.NewWriter(w)
+	cobra.CheckErr(out.Write(header))
+	for i := range columns[0] {
+		row := make([]string, len(columns))
+		for c := range columns {
+			row[c] = strconv.FormatFloat(columns[c][i], 'g', -1, 64)
+		}
+		cobra.CheckErr(out.Write(row))
+	}
+	out.Flush()
+	cobra.CheckErr(out.Error())
+}
+
+func isValidFormat(format string, formats ...string) bool {
+	for _, v := range formats {
+		if format == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pitchSize      int
+	pitchHop       int
+	fmin           float64
+	fmax           float64
+	pitchThreshold float64
+	pitchFormat    string
+)
+
+// pitchCmd represents the pitch command
+var pitchCmd = &cobra.Command{
+	Use:   "pitch",
+	Short: "Tracks the fundamental frequency of a track.",
+	Long: `Tracks the fundamental frequency of a monophonic track using the YIN algorithm.
+
+Writes the time, f0 in Hz, voicing confidence, voicing and MIDI note of every frame
+as csv or json to stdout unless --out is given. Unvoiced frames have an f0 of 0.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if !isValidFormat(pitchFormat, "csv", "json") {
+			return fmt.Errorf("invalid format specified: %s", pitchFormat)
+		}
+		if pitchSize < 4 || pitchHop < 1 || fmin <= 0 || fmax <= fmin {
+			return fmt.Errorf("invalid frame size, hop or frequency range: %d, %d, %f-%f", pitchSize, pitchHop, fmin, fmax)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		if sr := float64(track1.SampleRate()); float64(pitchSize/2) <= sr/fmax {
+			cobra.CheckErr(fmt.Errorf("frame size %d is too short for %g Hz at %g Hz, needs at least %d", pitchSize, fmax, sr, 2*(int(sr/fmax)+1)))
+		}
+		frames := track1.Pitch(pitchSize, pitchHop, fmin, fmax, pitchThreshold)
+
+		out := openOutput(cmd)
+		defer out.Close()
+		if pitchFormat == "json" {
+			printJSON(out, frames)
+			return
+		}
+		var times, freqs, confidence, voiced, midi []float64
+		for _, f := range frames {
+			times = append(times, f.Time)
+			freqs = append(freqs, f.Frequency)
+			confidence = append(confidence, f.Confidence)
+			midi = append(midi, f.MIDI)
+			if f.Voiced {
+				voiced = append(voiced, 1)
+			} else {
+				voiced = append(voiced, 0)
+			}
+		}
+		printCSV(out, []string{"time", "frequency", "confidence", "voiced", "midi"}, times, freqs, confidence, voiced, midi)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pitchCmd)
+	pitchCmd.Flags().IntVarP(&pitchSize, "size", "n", 2048, "Frame size in samples")
+	pitchCmd.Flags().IntVarP(&pitchHop, "hop", "H", 512, "Hop between frames in samples")
+	pitchCmd.Flags().Float64VarP(&fmin, "min", "m", 50.0, "Lowest frequency searched in Hz")
+	pitchCmd.Flags().Float64VarP(&fmax, "max", "M", 1000.0, "Highest frequency searched in Hz")
+	pitchCmd.Flags().Float64VarP(&pitchThreshold, "threshold", "t", 0.15, "YIN threshold, frames above it are unvoiced")
+	pitchCmd.Flags().StringVarP(&pitchFormat, "format", "F", "csv", "Output format (csv, json)")
+}
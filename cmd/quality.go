@@ -0,0 +1,75 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var qualitySize int
+
+// qualityReport is a dsp.Quality labelled with the files it compares.
+type qualityReport struct {
+	Reference string `json:"reference"`
+	Test      string `json:"test"`
+	dsp.Quality
+}
+
+// qualityCmd represents the quality command
+var qualityCmd = &cobra.Command{
+	Use:   "quality ref.wav test.wav",
+	Short: "Measures the quality of a processed track against its reference.",
+	Long: `Measures the SNR, segmental SNR, log-spectral distance and spectral convergence
+of a processed track against its reference, over the channels and samples they share.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if qualitySize < 2 {
+			return fmt.Errorf("invalid frame size: %d", qualitySize)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ref := dsp.NewWav()
+		ref.ReadFile(args[0])
+		test := dsp.NewWav()
+		test.ReadFile(args[1])
+
+		q := ref.Quality(test, qualitySize)
+		if jsonOutput {
+			printJSON(os.Stdout, qualityReport{Reference: args[0], Test: args[1], Quality: q})
+			return
+		}
+		fmt.Printf("%s against %s:\n", path.Base(args[1]), path.Base(args[0]))
+		fmt.Printf("SNR:                   %.2f dB\n", q.SNR)
+		fmt.Printf("Segmental SNR:         %.2f dB\n", q.SegmentalSNR)
+		fmt.Printf("Log-spectral distance: %.2f dB\n", q.LogSpectralDistance)
+		fmt.Printf("Spectral convergence:  %.4f\n", q.SpectralConvergence)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(qualityCmd)
+	qualityCmd.Flags().IntVarP(&qualitySize, "size", "s", 512, "Frame size in samples")
+	qualityCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Print as JSON")
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"math"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	matrix        string
+	matrixFile    string
+	centerLevel   float64
+	surroundLevel float64
+	lfeLevel      float64
+	normalizeMix  bool
+)
+
+func isValidMatrix(matrix string) bool {
+	for _, v := range dsp.Matrices {
+		if matrix == v {
+			return true
+		}
+	}
+	return false
+}
+
+// remixCmd represents the remix command
+var remixCmd = &cobra.Command{
+	Use:     "remix",
+	Aliases: []string{"downmix", "upmix"},
+	Short:   "Downmixes or upmixes a track's channels using a matrix.",
+	Long: `Downmixes or upmixes a track's channels using a matrix.
+
+Preset matrices:
+  itu    ITU-R BS.775 5.1/7.1 to stereo
+  loro   Dolby Lo/Ro 5.1/7.1 to stereo, -4.5 dB center and -6 dB surround by default
+  ltrt   Dolby Lt/Rt 5.1/7.1 to matrix encoded stereo, fixed Pro Logic II surround levels
+  upmix  Stereo to 5.1, with a full band LFE when --lfe is given
+
+A custom matrix can be given with --matrix-file, one row of coefficients per output channel.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if matrixFile == "" && !isValidMatrix(matrix) {
+			return fmt.Errorf("invalid matrix specified: %s", matrix)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		fmt.Printf("---------------\n%s details:\n", path.Base(file1))
+		track1.DumpHeader(false)
+
+		if cmd.CalledAs() == "upmix" && !cmd.Flags().Changed("matrix") {
+			matrix = "upmix"
+		}
+
+		var m dsp.Matrix
+		if matrixFile != "" {
+			fmt.Printf("Remixing %s using %s\n", path.Base(file1), path.Base(matrixFile))
+			m = dsp.ReadMatrixFile(matrixFile)
+		} else {
+			fmt.Printf("Remixing %s using %s matrix\n", path.Base(file1), matrix)
+			switch ch := track1.NumChannels(); {
+			case matrix == "upmix" && ch != 2:
+				fmt.Printf("upmix requires a stereo track, got %d channels\n", ch)
+				return
+			case matrix != "upmix" && ch != 6 && ch != 8:
+				fmt.Printf("%s requires a 5.1 or 7.1 track, got %d channels\n", matrix, ch)
+				return
+			}
+			lv := dsp.PresetMixLevels(matrix)
+			if cmd.Flags().Changed("center") {
+				lv.Center = centerLevel
+			}
+			if cmd.Flags().Changed("surround") {
+				lv.Surround = surroundLevel
+			}
+			if cmd.Flags().Changed("lfe") {
+				lv.LFE = lfeLevel
+			}
+			m = dsp.PresetMatrix(matrix, track1.NumChannels(), lv)
+		}
+		if m.Inputs() != track1.NumChannels() {
+			fmt.Printf("Matrix expects %d channels, track has %d\n", m.Inputs(), track1.NumChannels())
+			return
+		}
+		if normalizeMix {
+			m.Normalize()
+		}
+
+		track1.Remix(m)
+		track1.WriteFile(outFile)
+
+		fmt.Printf("Remixed into %d channels in %s.\n", track1.NumChannels(), outFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(remixCmd)
+	remixCmd.Flags().StringVarP(&matrix, "matrix", "m", "itu", "Preset matrix (itu, loro, ltrt, upmix)")
+	remixCmd.Flags().StringVarP(&matrixFile, "matrix-file", "M", "", "File with custom matrix coefficients")
+	remixCmd.Flags().Float64VarP(&centerLevel, "center", "c", dsp.DefaultMixLevels.Center, "Center mix level in dB, -4.5 for loro")
+	remixCmd.Flags().Float64VarP(&surroundLevel, "surround", "s", dsp.DefaultMixLevels.Surround, "Surround mix level in dB, -6 for loro")
+	remixCmd.Flags().Float64VarP(&lfeLevel, "lfe", "l", math.Inf(-1), "LFE mix level in dB, -Inf omits the LFE")
+	remixCmd.Flags().BoolVarP(&normalizeMix, "normalize", "n", false, "Scale the matrix so no channel can clip")
+}
@@ -0,0 +1,154 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"errors"
+	"fmt"
+	"image/png"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	sampleRate int
+	points     int
+	respLog    bool
+	respFormat string
+	respWidth  int
+	respHeight int
+	respFloor  float64
+)
+
+// filterCoefficients returns the transfer function of the named filter using the filter flags.
+func filterCoefficients(filter string) dsp.Coefficients {
+	switch filter {
+	case "avg":
+		return dsp.RollingAvgCoefficients(bandwidth)
+	case "windowedsinc":
+		return dsp.WindowedSincCoefficients(int(freq), bandwidth, sampleRate)
+	case "biquad":
+		return dsp.BiquadCoefficients(int(freq), lh, sampleRate)
+	case "highpass":
+		return dsp.HighpassCoefficients()
+	case "butter":
+		return dsp.ButterworthCoefficients(butterKind, order, freq, high, sampleRate)
+	case "cheb":
+		return dsp.ChebyshevCoefficients(freq, lh, ripple, poles, sampleRate)
+	default:
+		return dsp.CookbookCoefficients(sampleRate, cookbookBand(filter))
+	}
+}
+
+// responseCmd represents the response command
+var responseCmd = &cobra.Command{
+	Use:   "response",
+	Short: "Computes the frequency response of a filter.",
+	Long: `Computes the magnitude, phase and group delay of a filter over frequency.
+
+The filter takes the same flags as the filter command. csv and json are written to stdout
+unless --out is given, png plots magnitude over phase into ./out.png unless --out is given.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("requires a filter to be selected (avg, biquad, windowedsinc, highpass, cheb, butter, lpf, hpf, bpf, bpfskirt, notch, apf, peak, lowshelf, highshelf)")
+		}
+		if !isValidFilter(args[0]) {
+			return fmt.Errorf("invalid filter specified: %s", args[0])
+		}
+		if !isValidFormat(respFormat, "csv", "json", "png") {
+			return fmt.Errorf("invalid format specified: %s", respFormat)
+		}
+		if !isValidFormat(butterKind, dsp.Lowpass, dsp.Highpass, dsp.Bandpass, dsp.Bandstop) {
+			return fmt.Errorf("invalid Butterworth type specified: %s", butterKind)
+		}
+		switch args[0] {
+		case "cheb":
+			if err := checkChebyshev(sampleRate); err != nil {
+				return err
+			}
+		case "butter":
+			if err := checkButterworth(sampleRate); err != nil {
+				return err
+			}
+		case dsp.LPF, dsp.HPF, dsp.BPF, dsp.BPFSkirt, dsp.Notch, dsp.APF, dsp.PeakingEQ, dsp.LowShelf, dsp.HighShelf:
+			if err := cookbookBand(args[0]).Validate(sampleRate); err != nil {
+				return err
+			}
+		}
+		if points < 2 {
+			return fmt.Errorf("invalid number of points: %d", points)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := args[0]
+		c := filterCoefficients(filter)
+		low := 0.0
+		if respLog {
+			low = 10.0
+		}
+		r := c.Response(sampleRate, dsp.Frequencies(points, low, float64(sampleRate)/2, respLog))
+
+		switch respFormat {
+		case "png":
+			out := imageOutput(cmd, "./out.png")
+			f, err := os.Create(out)
+			cobra.CheckErr(err)
+			defer f.Close()
+			cobra.CheckErr(png.Encode(f, r.Image(respWidth, respHeight, respFloor, respLog)))
+			fmt.Printf("Plotted %s response into %s.\n", filter, out)
+		case "json":
+			out := openOutput(cmd)
+			defer out.Close()
+			printJSON(out, struct {
+				Filter       string           `json:"filter"`
+				SampleRate   int              `json:"sampleRate"`
+				Coefficients dsp.Coefficients `json:"coefficients"`
+				dsp.Response
+			}{filter, sampleRate, c, r})
+		default:
+			out := openOutput(cmd)
+			defer out.Close()
+			printCSV(out, []string{"frequency", "magnitude", "phase", "group_delay"}, r.Frequencies, r.Magnitude, r.Phase, r.GroupDelay)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(responseCmd)
+	responseCmd.Flags().IntVarP(&lh, "lh", "l", 0, "Low pass: 0, High pass: 1")
+	responseCmd.Flags().Float64VarP(&freq, "freq", "f", 5000, "Cut off frequency, the low edge for band filters")
+	responseCmd.Flags().IntVarP(&bandwidth, "bandwidth", "b", 20, "Roll off value for certain filters")
+	responseCmd.Flags().Float64VarP(&ripple, "ripple", "r", 0.5, "Passband ripple in percent for Chebyshev")
+	responseCmd.Flags().IntVarP(&poles, "poles", "p", 4, "Even number of poles for Chebyshev, 2 to 20")
+	responseCmd.Flags().StringVarP(&butterKind, "type", "t", dsp.Lowpass, "Butterworth type (lowpass, highpass, bandpass, bandstop)")
+	responseCmd.Flags().IntVarP(&order, "order", "O", 4, "Order of Butterworth, doubled for band filters")
+	responseCmd.Flags().Float64VarP(&high, "high", "H", 10000, "High edge of Butterworth band filters")
+	responseCmd.Flags().Float64VarP(&bandQ, "q", "q", 0.7071, "Q of cookbook filters")
+	responseCmd.Flags().Float64VarP(&octaves, "octaves", "w", 0, "Bandwidth in octaves of cookbook filters, overrides Q")
+	responseCmd.Flags().Float64VarP(&slope, "slope", "s", 0, "Slope of cookbook shelves, overrides Q")
+	responseCmd.Flags().Float64VarP(&bandGain, "gain", "G", 0, "Gain in dB of cookbook peaking and shelving filters")
+	responseCmd.Flags().IntVarP(&sampleRate, "rate", "S", 44100, "Sample rate")
+	responseCmd.Flags().IntVarP(&points, "points", "n", 512, "Number of frequencies evaluated")
+	responseCmd.Flags().BoolVarP(&respLog, "log", "g", false, "Logarithmic frequency axis from 10 Hz")
+	responseCmd.Flags().StringVarP(&respFormat, "format", "F", "csv", "Output format (csv, json, png)")
+	responseCmd.Flags().IntVarP(&respWidth, "width", "W", 800, "Plot width")
+	responseCmd.Flags().IntVarP(&respHeight, "height", "y", 600, "Plot height")
+	responseCmd.Flags().Float64VarP(&respFloor, "floor", "m", -60.0, "Lowest magnitude plotted in dB")
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"image/png"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	stftSize   int
+	hopSize    int
+	stftWindow string
+	sgWidth    int
+	sgHeight   int
+	sgFloor    float64
+	sgLog      bool
+)
+
+// spectrogramCmd represents the spectrogram command
+var spectrogramCmd = &cobra.Command{
+	Use:   "spectrogram",
+	Short: "Renders a spectrogram of a track to PNG.",
+	Long: `Renders the short-time Fourier transform of a track to a PNG, ./out.png unless --out is given.
+
+Time runs left to right and frequency bottom to top, colored from --floor up to 0 dB.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if _, ok := dsp.Windows[stftWindow]; !ok {
+			return fmt.Errorf("invalid window specified: %s", stftWindow)
+		}
+		if stftSize < 2 || hopSize < 1 || sgHeight < 1 {
+			return fmt.Errorf("invalid FFT size, hop or height: %d, %d, %d", stftSize, hopSize, sgHeight)
+		}
+		if sgFloor >= 0 {
+			return fmt.Errorf("invalid floor, must be below 0 dB: %g", sgFloor)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+		out := imageOutput(cmd, "./out.png")
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		fmt.Printf("---------------\n%s details:\n", path.Base(file1))
+		track1.DumpHeader(false)
+
+		fmt.Printf("Computing STFT (N=%d, hop=%d, window=%s)...\n", stftSize, hopSize, stftWindow)
+		s := track1.STFT(stftSize, hopSize, stftWindow)
+		img := s.Image(sgWidth, sgHeight, sgFloor, sgLog)
+
+		f, err := os.Create(out)
+		cobra.CheckErr(err)
+		defer f.Close()
+		cobra.CheckErr(png.Encode(f, img))
+
+		fmt.Printf("Rendered into %s.\n", out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(spectrogramCmd)
+	spectrogramCmd.Flags().IntVarP(&stftSize, "size", "n", 2048, "FFT size")
+	spectrogramCmd.Flags().IntVarP(&hopSize, "hop", "H", 512, "Hop between frames in samples")
+	spectrogramCmd.Flags().StringVarP(&stftWindow, "window", "w", "hann", "Window function (rectangular, hann, hamming, blackman, bartlett, flattop)")
+	spectrogramCmd.Flags().IntVarP(&sgWidth, "width", "W", 0, "Image width, 0 for one column per frame")
+	spectrogramCmd.Flags().IntVarP(&sgHeight, "height", "y", 512, "Image height")
+	spectrogramCmd.Flags().Float64VarP(&sgFloor, "floor", "f", -120.0, "Lowest magnitude shown in dB")
+	spectrogramCmd.Flags().BoolVarP(&sgLog, "log", "g", false, "Logarithmic frequency axis")
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fftSize    int
+	windowName string
+	start      float64
+	end        float64
+	numPeaks   int
+	format     string
+)
+
+// spectrumCmd represents the spectrum command
+var spectrumCmd = &cobra.Command{
+	Use:   "spectrum",
+	Short: "Computes the averaged magnitude spectrum of a track.",
+	Long: `Computes the averaged, windowed magnitude spectrum of a track or a time range of it.
+
+Magnitudes are in dB relative to a full scale sine. The text format lists the strongest peaks,
+csv and json export the whole spectrum, to stdout unless --out is given.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if _, ok := dsp.Windows[windowName]; !ok {
+			return fmt.Errorf("invalid window specified: %s", windowName)
+		}
+		if !isValidFormat(format, "text", "csv", "json") {
+			return fmt.Errorf("invalid format specified: %s", format)
+		}
+		if fftSize < 2 {
+			return fmt.Errorf("invalid FFT size: %d", fftSize)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+
+		s := track1.Spectrum(fftSize, windowName, start, end)
+		peaks := s.Peaks(numPeaks)
+
+		out := openOutput(cmd)
+		defer out.Close()
+		switch format {
+		case "csv":
+			printCSV(out, []string{"frequency", "magnitude"}, s.Frequencies, s.Magnitudes)
+		case "json":
+			printJSON(out, struct {
+				File  string             `json:"file"`
+				Peaks []dsp.SpectralPeak `json:"peaks"`
+				dsp.Spectrum
+			}{file1, peaks, s})
+		default:
+			fmt.Fprintf(out, "---------------\n%s peaks:\n", path.Base(file1))
+			for _, p := range peaks {
+				fmt.Fprintf(out, "%10.1f Hz %8.2f dB\n", p.Frequency, p.Magnitude)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(spectrumCmd)
+	spectrumCmd.Flags().IntVarP(&fftSize, "size", "n", 4096, "FFT size")
+	spectrumCmd.Flags().StringVarP(&windowName, "window", "w", "hann", "Window function (rectangular, hann, hamming, blackman, bartlett, flattop)")
+	spectrumCmd.Flags().Float64VarP(&start, "start", "s", 0, "Start time in seconds")
+	spectrumCmd.Flags().Float64VarP(&end, "end", "e", 0, "End time in seconds, 0 for the end of the track")
+	spectrumCmd.Flags().IntVarP(&numPeaks, "peaks", "p", 5, "Number of peaks to detect")
+	spectrumCmd.Flags().StringVarP(&format, "format", "F", "text", "Output format (text, csv, json)")
+}
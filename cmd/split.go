@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	splitBy        string
+	splitThreshold float64
+	splitSilence   float64
+	splitHold      float64
+	every          float64
+	at             []float64
+	template       string
+)
+
+// splitCmd represents the split command
+var splitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "Splits a track into several files.",
+	Long: `Splits a track into several files at silences, every few seconds, at given times or at cue markers.
+Output names come from a template where {name} is the input name without extension,
+{n} the piece number from 1, and {start} and {end} the piece bounds in seconds.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if !isValidFormat(splitBy, "silence", "duration", "times", "cues") {
+			return fmt.Errorf("invalid split mode specified: %s", splitBy)
+		}
+		if !strings.Contains(template, "{n}") && !strings.Contains(template, "{start}") && !strings.Contains(template, "{end}") {
+			return fmt.Errorf("template %s needs {n}, {start} or {end} so pieces do not overwrite each other", template)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		fmt.Printf("---------------\n%s details:\n", path.Base(file1))
+		track1.DumpHeader(false)
+
+		var points []float64
+		switch splitBy {
+		case "silence":
+			points = track1.SilenceSplits(splitThreshold, splitSilence, splitHold)
+		case "duration":
+			points = track1.EverySplits(every)
+		case "times":
+			points = at
+		case "cues":
+			points = track1.Cues()
+		}
+
+		pieces, regions := track1.Split(points)
+		name := strings.TrimSuffix(path.Base(file1), path.Ext(file1))
+		digits := len(fmt.Sprint(len(pieces)))
+		for i, piece := range pieces {
+			out := strings.NewReplacer(
+				"{name}", name,
+				"{n}", fmt.Sprintf("%0*d", digits, i+1),
+				"{start}", fmt.Sprintf("%.3f", regions[i].Start),
+				"{end}", fmt.Sprintf("%.3f", regions[i].End),
+			).Replace(template)
+			piece.WriteFile(out)
+			fmt.Printf("Wrote %.3fs to %.3fs to %s.\n", regions[i].Start, regions[i].End, out)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+	splitCmd.Flags().StringVarP(&splitBy, "by", "b", "silence", "Split at: silence, duration, times or cues")
+	splitCmd.Flags().Float64VarP(&splitThreshold, "threshold", "t", -50.0, "Silence threshold in dBFS")
+	splitCmd.Flags().Float64VarP(&splitSilence, "duration", "d", 0.5, "Shortest silence in seconds to split at")
+	splitCmd.Flags().Float64VarP(&splitHold, "hold", "H", 0.05, "Time in seconds sound holds off silence after it")
+	splitCmd.Flags().Float64VarP(&every, "every", "e", 60.0, "Length of each piece in seconds when splitting by duration")
+	splitCmd.Flags().Float64SliceVarP(&at, "at", "a", nil, "Times in seconds to split at when splitting by times")
+	splitCmd.Flags().StringVarP(&template, "template", "T", "{name}_{n}.wav", "Output file name template")
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+// fileStats is the per channel dsp.ChannelStats of a file.
+type fileStats struct {
+	File     string             `json:"file"`
+	Channels []dsp.ChannelStats `json:"channels"`
+}
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Prints level and quality statistics of tracks.",
+	Long: `Prints the DC offset, peak, RMS, crest factor, dynamic range, noise floor,
+zero-crossing rate and number of clipped samples of every channel of one or more tracks.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var results []fileStats
+		for _, file := range args {
+			track := dsp.NewWav()
+			track.ReadFile(file)
+			stats := track.Stats()
+			if jsonOutput {
+				results = append(results, fileStats{File: file, Channels: stats})
+				continue
+			}
+			fmt.Printf("%s:\n", path.Base(file))
+			for i, s := range stats {
+				fmt.Printf("Channel %d:\n", i+1)
+				fmt.Printf("  DC offset:      %.4f%%\n", 100*s.DCOffset)
+				fmt.Printf("  Peak:           %.2f dBFS\n", s.PeakDBFS)
+				fmt.Printf("  RMS:            %.2f dBFS\n", s.RMSDBFS)
+				fmt.Printf("  Crest factor:   %.2f dB\n", s.CrestFactor)
+				fmt.Printf("  Dynamic range:  %.2f dB\n", s.DynamicRange)
+				fmt.Printf("  Noise floor:    %.2f dBFS\n", s.NoiseFloor)
+				fmt.Printf("  Zero crossings: %.1f/s\n", s.ZeroCrossingRate)
+				fmt.Printf("  Clipped:        %d samples\n", s.Clipped)
+			}
+		}
+		if jsonOutput {
+			printJSON(os.Stdout, results)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Print as JSON")
+}
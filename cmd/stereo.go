@@ -0,0 +1,109 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"image/png"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	stereoWindow    float64
+	stereoThreshold float64
+	stereoFormat    string
+	lissajous       string
+	lissajousSize   int
+)
+
+// stereoCmd represents the stereo-analyze command
+var stereoCmd = &cobra.Command{
+	Use:   "stereo-analyze",
+	Short: "Analyzes the stereo image of a track.",
+	Long: `Measures the phase correlation, L/R balance and mid/side ratio of a stereo track
+over short windows, and flags mono compatibility problems and inverted polarity.
+
+text prints a summary, csv the windows and json both, to stdout unless --out is given.
+With --lissajous a vectorscope of the track is rendered to that PNG.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if !isValidFormat(stereoFormat, "text", "csv", "json") {
+			return fmt.Errorf("invalid format specified: %s", stereoFormat)
+		}
+		if stereoWindow <= 0 || lissajousSize < 1 {
+			return fmt.Errorf("invalid window or size: %f, %d", stereoWindow, lissajousSize)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		a := track1.StereoAnalysis(stereoWindow, stereoThreshold)
+
+		if lissajous != "" {
+			f, err := os.Create(lissajous)
+			cobra.CheckErr(err)
+			defer f.Close()
+			cobra.CheckErr(png.Encode(f, track1.Lissajous(lissajousSize)))
+		}
+
+		out := openOutput(cmd)
+		defer out.Close()
+		switch stereoFormat {
+		case "json":
+			printJSON(out, a)
+		case "csv":
+			var times, corr, balance, midSide []float64
+			for _, f := range a.Frames {
+				times = append(times, f.Time)
+				corr = append(corr, f.Correlation)
+				balance = append(balance, f.Balance)
+				midSide = append(midSide, f.MidSide)
+			}
+			printCSV(out, []string{"time", "correlation", "balance", "midSide"}, times, corr, balance, midSide)
+		default:
+			fmt.Fprintf(out, "%s:\n", path.Base(file1))
+			fmt.Fprintf(out, "Correlation: %.3f\n", a.Correlation)
+			fmt.Fprintf(out, "Balance:     %.2f dB\n", a.Balance)
+			fmt.Fprintf(out, "Mid/side:    %.2f dB\n", a.MidSide)
+			fmt.Fprintf(out, "Mono loss:   %.2f dB\n", a.MonoLoss)
+			if a.PolarityInverted {
+				fmt.Fprintln(out, "Polarity of one channel appears inverted.")
+			}
+			for _, r := range a.MonoProblems {
+				fmt.Fprintf(out, "Mono compatibility problem from %.3fs to %.3fs\n", r.Start, r.End)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stereoCmd)
+	stereoCmd.Flags().Float64VarP(&stereoWindow, "window", "w", 0.1, "Window length in seconds")
+	stereoCmd.Flags().Float64VarP(&stereoThreshold, "threshold", "t", 0.0, "Correlation below which a window is a mono compatibility problem")
+	stereoCmd.Flags().StringVarP(&stereoFormat, "format", "F", "text", "Output format (text, csv, json)")
+	stereoCmd.Flags().StringVarP(&lissajous, "lissajous", "l", "", "PNG file to render a vectorscope into")
+	stereoCmd.Flags().IntVarP(&lissajousSize, "size", "s", 512, "Vectorscope width and height")
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncWindow   float64
+	syncHop      float64
+	syncMaxShift float64
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Corrects the offset and clock drift of a track against a reference.",
+	Long: `Measures the lag of the second track behind the first over windows spread across
+the whole recording, fits it as an offset plus a drift in ppm, and resamples the second
+track to line up with the first. The corrected track is written to --out.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if syncWindow <= 0 || syncHop <= 0 || syncMaxShift < 0 {
+			return fmt.Errorf("invalid window, hop or max shift: %f, %f, %f", syncWindow, syncHop, syncMaxShift)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+		file2 := args[1]
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		track2 := dsp.NewWav()
+		track2.ReadFile(file2)
+
+		d := track1.Drift(track2, syncWindow, syncHop, syncMaxShift)
+		track2.Resync(d.Offset, d.Rate)
+		track2.WriteFile(outFile)
+
+		if jsonOutput {
+			printJSON(os.Stdout, d)
+			return
+		}
+		fmt.Printf("Syncing %s to %s\n", path.Base(file2), path.Base(file1))
+		for _, p := range d.Points {
+			fmt.Printf("  %9.3fs: lag %9.3fms (confidence %.2f)\n", p.Time, 1000*p.Lag, p.Confidence)
+		}
+		fmt.Printf("Offset: %.3fms\n", 1000*d.Offset)
+		fmt.Printf("Drift:  %.3f ppm\n", 1e6*d.Rate)
+		fmt.Printf("Synced into %s.\n", outFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().Float64VarP(&syncWindow, "window", "w", 10.0, "Length in seconds of each window correlated")
+	syncCmd.Flags().Float64VarP(&syncHop, "hop", "H", 60.0, "Time in seconds between windows")
+	syncCmd.Flags().Float64VarP(&syncMaxShift, "max-shift", "m", 1.0, "Largest lag in seconds searched")
+	syncCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Print as JSON")
+}
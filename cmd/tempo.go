@@ -0,0 +1,77 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	minBPM float64
+	maxBPM float64
+)
+
+// fileTempo is a dsp.Tempo labelled with the file it was estimated from.
+type fileTempo struct {
+	File string `json:"file"`
+	dsp.Tempo
+}
+
+// tempoCmd represents the tempo command
+var tempoCmd = &cobra.Command{
+	Use:   "tempo",
+	Short: "Estimates the tempo of tracks.",
+	Long: `Estimates the tempo of one or more tracks in BPM with a confidence between 0 and 1,
+and the time of the first beat of the beat grid.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if minBPM <= 0 || maxBPM < minBPM {
+			return fmt.Errorf("invalid BPM range: %f-%f", minBPM, maxBPM)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var results []fileTempo
+		for _, file := range args {
+			track := dsp.NewWav()
+			track.ReadFile(file)
+			t := track.Tempo(minBPM, maxBPM)
+			if jsonOutput {
+				results = append(results, fileTempo{File: file, Tempo: t})
+				continue
+			}
+			fmt.Printf("%s: %.1f BPM (confidence %.2f, first beat at %.3fs)\n", path.Base(file), t.BPM, t.Confidence, t.Offset)
+		}
+		if jsonOutput {
+			printJSON(os.Stdout, results)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tempoCmd)
+	tempoCmd.Flags().Float64VarP(&minBPM, "min", "m", 60.0, "Lowest tempo considered in BPM")
+	tempoCmd.Flags().Float64VarP(&maxBPM, "max", "M", 200.0, "Highest tempo considered in BPM")
+	tempoCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Print as JSON")
+}
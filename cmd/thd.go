@@ -0,0 +1,134 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	toneFreq    float64
+	toneLevel   float64
+	toneSeconds float64
+	toneRate    int
+	effect      string
+	thdSize     int
+	harmonics   int
+)
+
+// effects are the processes a test tone can be passed through before measuring it.
+var effects = []string{"none", "compress", "limit", "avg", "biquad", "windowedsinc", "highpass"}
+
+// applyEffect runs the named effect on track with the flags shared with its own command.
+func applyEffect(track *dsp.Wav, name string) {
+	switch name {
+	case "compress":
+		track.Compress(threshold, ratio, att, rel, 10, knee, gain, makeup)
+	case "limit":
+		track.Limit(ceiling, 5, 50)
+	case "avg":
+		track.RollingAvgLowpass(bandwidth)
+	case "biquad":
+		track.Biquad(int(freq), lh)
+	case "windowedsinc":
+		track.WindowedSinc(int(freq), bandwidth)
+	case "highpass":
+		track.Highpass()
+	}
+}
+
+// thdCmd represents the thd command
+var thdCmd = &cobra.Command{
+	Use:   "thd [file]",
+	Short: "Measures the harmonic distortion of a sine test tone.",
+	Long: `Measures the THD, THD+N and SINAD of a sine test tone.
+
+Without a file a tone is generated with --frequency, --level, --seconds and --rate.
+The tone is passed through --effect first, which takes the same flags as the compress
+and filter commands, and --ceiling in dBFS for the sample peak limiter. The processed tone is
+written to --out when it is given.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if !isValidFormat(effect, effects...) {
+			return fmt.Errorf("invalid effect specified: %s", effect)
+		}
+		if thdSize < 2 {
+			return fmt.Errorf("invalid FFT size: %d", thdSize)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var track *dsp.Wav
+		name := fmt.Sprintf("%g Hz tone at %g dBFS", toneFreq, toneLevel)
+		if len(args) > 0 {
+			track = dsp.NewWav()
+			track.ReadFile(args[0])
+			name = path.Base(args[0])
+		} else {
+			track = dsp.NewSine(toneRate, 1, toneFreq, toneLevel, toneSeconds)
+		}
+		applyEffect(track, effect)
+		if cmd.Flags().Changed("out") {
+			track.WriteFile(outFile)
+		}
+
+		d := track.Distortion(thdSize, harmonics)
+		if jsonOutput {
+			printJSON(os.Stdout, d)
+			return
+		}
+		fmt.Printf("%s through %s:\n", name, effect)
+		fmt.Printf("Fundamental: %.2f Hz at %.2f dBFS\n", d.Fundamental, d.Level)
+		fmt.Printf("THD:         %.4f%% (%.2f dB)\n", d.THD, d.THDDB)
+		fmt.Printf("THD+N:       %.4f%% (%.2f dB)\n", d.THDN, d.THDNDB)
+		fmt.Printf("SINAD:       %.2f dB\n", d.SINAD)
+		for i, h := range d.Harmonics {
+			fmt.Printf("  H%-2d %.2f dB\n", i+2, h)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(thdCmd)
+	thdCmd.Flags().Float64VarP(&toneFreq, "frequency", "F", 1000.0, "Frequency of the generated tone in Hz")
+	thdCmd.Flags().Float64VarP(&toneLevel, "level", "L", -3.0, "Level of the generated tone in dBFS")
+	thdCmd.Flags().Float64VarP(&toneSeconds, "seconds", "s", 2.0, "Length of the generated tone in seconds")
+	thdCmd.Flags().IntVarP(&toneRate, "rate", "S", 48000, "Sample rate of the generated tone")
+	thdCmd.Flags().StringVarP(&effect, "effect", "e", "none", "Effect applied to the tone (none, compress, limit, avg, biquad, windowedsinc, highpass)")
+	thdCmd.Flags().IntVarP(&thdSize, "size", "n", 16384, "FFT size")
+	thdCmd.Flags().IntVarP(&harmonics, "harmonics", "H", 10, "Highest harmonic included in THD")
+	thdCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Print as JSON")
+
+	thdCmd.Flags().Float64Var(&threshold, "threshold", -12.0, "Compression threshold in dB")
+	thdCmd.Flags().Float64Var(&ratio, "ratio", 2.0, "Compression ratio")
+	thdCmd.Flags().Float64Var(&att, "attack", 10.0, "Compression attack time in ms")
+	thdCmd.Flags().Float64Var(&rel, "release", 300.0, "Compression release time in ms")
+	thdCmd.Flags().Float64Var(&knee, "knee", -25.0, "Compression soft knee width in dB")
+	thdCmd.Flags().BoolVar(&makeup, "makeup", false, "Apply makeup gain")
+	thdCmd.Flags().Float64Var(&gain, "gain", -1.0, "Makeup gain in dB")
+	thdCmd.Flags().Float64Var(&ceiling, "ceiling", -1.0, "Limiter ceiling in dBFS")
+	thdCmd.Flags().IntVarP(&lh, "lh", "l", 0, "Low pass: 0, High pass: 1")
+	thdCmd.Flags().Float64VarP(&freq, "freq", "f", 5000, "Cut off frequency")
+	thdCmd.Flags().IntVarP(&bandwidth, "bandwidth", "b", 20, "Roll off value for certain filters")
+}
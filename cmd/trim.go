@@ -0,0 +1,66 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"path"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	silenceThreshold float64
+	minSilence       float64
+	hold             float64
+	pad              float64
+	fadeIn           float64
+	fadeOut          float64
+)
+
+// trimCmd represents the trim command
+var trimCmd = &cobra.Command{
+	Use:   "trim",
+	Short: "Trims leading and trailing silence from a track.",
+	Long:  `Trims leading and trailing silence from a track, with optional padding and fades.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+		fmt.Printf("Trimming silence below %f dBFS from %s\n", silenceThreshold, path.Base(file1))
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		fmt.Printf("---------------\n%s details:\n", path.Base(file1))
+		track1.DumpHeader(false)
+
+		kept := track1.Trim(silenceThreshold, minSilence, hold, pad, fadeIn, fadeOut)
+		track1.WriteFile(outFile)
+
+		fmt.Printf("Kept %.3fs to %.3fs in %s.\n", kept.Start, kept.End, outFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trimCmd)
+	trimCmd.Flags().Float64VarP(&silenceThreshold, "threshold", "t", -50.0, "Silence threshold in dBFS")
+	trimCmd.Flags().Float64VarP(&minSilence, "duration", "d", 0.1, "Shortest silence in seconds")
+	trimCmd.Flags().Float64VarP(&hold, "hold", "H", 0.05, "Time in seconds sound holds off silence after it")
+	trimCmd.Flags().Float64VarP(&pad, "pad", "p", 0.0, "Silence in seconds kept before and after the sound")
+	trimCmd.Flags().Float64VarP(&fadeIn, "fade-in", "i", 0.0, "Fade in time in seconds")
+	trimCmd.Flags().Float64VarP(&fadeOut, "fade-out", "O", 0.0, "Fade out time in seconds")
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2021 hacel <hasel@ammasa.net>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cmd
+
+import (
+	"dsp/dsp"
+	"fmt"
+	"image/png"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	waveWidth  int
+	waveHeight int
+	waveStart  float64
+	waveEnd    float64
+	dbScale    bool
+	waveFloor  float64
+)
+
+// waveformCmd represents the waveform command
+var waveformCmd = &cobra.Command{
+	Use:   "waveform",
+	Short: "Renders the waveform of a track to PNG or SVG.",
+	Long: `Renders the min/max waveform and RMS envelope of each channel of a track,
+to ./out.png unless --out is given. An --out ending in .svg renders an SVG.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if waveWidth < 1 || waveHeight < 1 {
+			return fmt.Errorf("invalid width or height: %d, %d", waveWidth, waveHeight)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		file1 := args[0]
+		out := imageOutput(cmd, "./out.png")
+
+		track1 := dsp.NewWav()
+		track1.ReadFile(file1)
+		fmt.Printf("---------------\n%s details:\n", path.Base(file1))
+		track1.DumpHeader(false)
+
+		wf := track1.Waveform(waveWidth, waveStart, waveEnd)
+		f, err := os.Create(out)
+		cobra.CheckErr(err)
+		defer f.Close()
+		if strings.EqualFold(path.Ext(out), ".svg") {
+			cobra.CheckErr(wf.WriteSVG(f, waveHeight, dbScale, waveFloor))
+		} else {
+			cobra.CheckErr(png.Encode(f, wf.Image(waveHeight, dbScale, waveFloor)))
+		}
+
+		fmt.Printf("Rendered into %s.\n", out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(waveformCmd)
+	waveformCmd.Flags().IntVarP(&waveWidth, "width", "W", 1200, "Image width")
+	waveformCmd.Flags().IntVarP(&waveHeight, "height", "y", 300, "Image height")
+	waveformCmd.Flags().Float64VarP(&waveStart, "start", "s", 0, "Start time in seconds")
+	waveformCmd.Flags().Float64VarP(&waveEnd, "end", "e", 0, "End time in seconds, 0 for the end of the track")
+	waveformCmd.Flags().BoolVarP(&dbScale, "db", "d", false, "Logarithmic amplitude scale")
+	waveformCmd.Flags().Float64VarP(&waveFloor, "floor", "f", -60.0, "Lowest level shown on the dB scale")
+}
@@ -0,0 +1,72 @@
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/mjibson/go-dsp/fft"
+)
+
+// crossCorrelation returns the circular cross-correlation of a and b computed with the FFT,
+// zero padded so lags in either direction do not wrap into each other. Index l holds the sum
+// of a[n]*b[n+l], negative lags are at the end. With phat set the cross spectrum is whitened
+// (GCC-PHAT), which sharpens the peak for reverberant or coloured signals.
+func crossCorrelation(a, b []float64, phat bool) []float64 {
+	size := 1
+	for size < len(a)+len(b) {
+		size *= 2
+	}
+	x := make([]float64, size)
+	y := make([]float64, size)
+	copy(x, a)
+	copy(y, b)
+	X := fft.FFTReal(x)
+	Y := fft.FFTReal(y)
+	for i := range X {
+		X[i] = Y[i] * cmplx.Conj(X[i])
+		if phat {
+			if m := cmplx.Abs(X[i]); m > 1e-12 {
+				X[i] /= complex(m, 0)
+			}
+		}
+	}
+	r := make([]float64, size)
+	for i, v := range fft.IFFT(X) {
+		r[i] = real(v)
+	}
+	return r
+}
+
+// lag returns the delay in samples of b relative to a within maxLag, from the peak of their cross-correlation.
+func lag(a, b []float64, maxLag int, phat bool) int {
+	r := crossCorrelation(a, b, phat)
+	maxLag = int(math.Min(float64(maxLag), float64(len(r)/2-1)))
+	best, bestLag := math.Inf(-1), 0
+	for l := -maxLag; l <= maxLag; l++ {
+		if v := r[(l+len(r))%len(r)]; v > best {
+			best, bestLag = v, l
+		}
+	}
+	return bestLag
+}
+
+// Lag returns how many frames b lags behind Wav, searched within maxLag frames either way.
+// Channels are mixed to mono first. With phat set the generalized cross-correlation
+// with phase transform is used.
+func (w *Wav) Lag(b *Wav, maxLag int, phat bool) int {
+	return lag(w.mono(), b.mono(), maxLag, phat)
+}
+
+// Shift delays Wav by the given number of frames, padding the start with silence.
+// A negative shift advances it instead, dropping frames from the start.
+func (w *Wav) Shift(frames int) {
+	chans := w.Channels()
+	for c, ch := range chans {
+		if frames >= 0 {
+			chans[c] = append(make([]float64, frames), ch...)
+		} else {
+			chans[c] = ch[int(math.Min(float64(-frames), float64(len(ch)))):]
+		}
+	}
+	w.SetChannels(chans)
+}
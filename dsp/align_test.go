@@ -0,0 +1,29 @@
+package dsp
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestLag(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	x := make([]float64, 8000)
+	for i := range x {
+		x[i] = 1000 * rng.NormFloat64()
+	}
+	a := newTestWav(8000, x)
+
+	for _, want := range []int{37, -120} {
+		b := newTestWav(8000, x)
+		b.Shift(want)
+		for _, phat := range []bool{false, true} {
+			if got := a.Lag(b, 400, phat); got != want {
+				t.Errorf("got lag %d with phat %v, wanted %d", got, phat, want)
+			}
+		}
+		b.Shift(-want)
+		if got := a.Lag(b, 400, false); got != 0 {
+			t.Errorf("got lag %d after shifting back, wanted 0", got)
+		}
+	}
+}
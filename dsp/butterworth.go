@@ -0,0 +1,152 @@
+package dsp
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// Kinds of Butterworth filter.
+const (
+	Lowpass  = "lowpass"
+	Highpass = "highpass"
+	Bandpass = "bandpass"
+	Bandstop = "bandstop"
+)
+
+// section returns the biquad with the given pair of poles and pair of zeros in the z-plane.
+// A zero or pole at the origin leaves a first order section.
+func section(p1, p2, z1, z2 complex128) biquad {
+	return biquad{
+		a1: 1,
+		a2: -real(z1 + z2),
+		a3: real(z1 * z2),
+		b1: -real(p1 + p2),
+		b2: real(p1 * p2),
+	}
+}
+
+// normalize scales the feedforward coefficients of the section for unity gain at angular frequency omega.
+func (f *biquad) normalize(omega float64) {
+	h, _ := f.coefficients().at(omega)
+	g := cmplx.Abs(h)
+	f.a1 /= g
+	f.a2 /= g
+	f.a3 /= g
+}
+
+// butterworthSections returns a Butterworth filter of the given kind and order as second order sections.
+// Low and high pass use low as the cutoff, band pass and band stop run from low to high Hz.
+// Band filters have twice the order of their low pass prototype.
+func butterworthSections(kind string, order int, low, high, sr float64) []biquad {
+	bilinear := func(s complex128) complex128 {
+		return (complex(2*sr, 0) + s) / (complex(2*sr, 0) - s)
+	}
+	// Prewarped analog frequencies and the digital one each section is normalized at.
+	w1 := 2 * sr * math.Tan(math.Pi*low/sr)
+	w2 := 2 * sr * math.Tan(math.Pi*high/sr)
+	w0 := math.Sqrt(w1 * w2)
+	bw := w2 - w1
+	var ref float64
+	switch kind {
+	case Highpass:
+		ref = math.Pi
+	case Bandpass:
+		ref = 2 * math.Atan(w0/(2*sr))
+	}
+	notch := cmplx.Exp(complex(0, 2*math.Atan(w0/(2*sr))))
+
+	var sections []biquad
+	add := func(p1, p2 complex128, first bool) {
+		var f biquad
+		switch kind {
+		case Lowpass:
+			if first {
+				f = section(bilinear(p1), 0, -1, 0)
+			} else {
+				f = section(bilinear(p1), bilinear(p2), -1, -1)
+			}
+		case Highpass:
+			if first {
+				f = section(bilinear(p1), 0, 1, 0)
+			} else {
+				f = section(bilinear(p1), bilinear(p2), 1, 1)
+			}
+		case Bandpass:
+			f = section(bilinear(p1), bilinear(p2), 1, -1)
+		case Bandstop:
+			f = section(bilinear(p1), bilinear(p2), notch, cmplx.Conj(notch))
+		}
+		f.normalize(ref)
+		sections = append(sections, f)
+	}
+
+	for k := 0; k < order; k++ {
+		// Prototype poles on the left half of the unit circle, one of each conjugate pair.
+		p := cmplx.Exp(complex(0, math.Pi*float64(2*k+order+1)/float64(2*order)))
+		if imag(p) < -1e-12 {
+			continue
+		}
+		onAxis := math.Abs(imag(p)) <= 1e-12
+		if onAxis {
+			p = complex(-1, 0)
+		}
+		switch kind {
+		case Lowpass:
+			add(p*complex(w1, 0), cmplx.Conj(p*complex(w1, 0)), onAxis)
+		case Highpass:
+			add(complex(w1, 0)/p, cmplx.Conj(complex(w1, 0)/p), onAxis)
+		case Bandpass, Bandstop:
+			// Each prototype pole becomes the two roots of a quadratic.
+			var s1, s2 complex128
+			if kind == Bandpass {
+				d := cmplx.Sqrt(p*p*complex(bw*bw, 0) - complex(4*w0*w0, 0))
+				s1, s2 = (p*complex(bw, 0)+d)/2, (p*complex(bw, 0)-d)/2
+			} else {
+				d := cmplx.Sqrt(complex(bw*bw, 0) - 4*p*p*complex(w0*w0, 0))
+				s1, s2 = (complex(bw, 0)+d)/(2*p), (complex(bw, 0)-d)/(2*p)
+			}
+			if onAxis {
+				add(s1, s2, false)
+			} else {
+				add(s1, cmplx.Conj(s1), false)
+				add(s2, cmplx.Conj(s2), false)
+			}
+		}
+	}
+	return sections
+}
+
+// checkButterworth panics on a kind, order or frequencies Butterworth cannot design.
+func checkButterworth(kind string, order int, low, high, sr float64) {
+	switch kind {
+	case Lowpass, Highpass:
+		high = low
+	case Bandpass, Bandstop:
+		if high <= low {
+			panic("High edge must be above the low edge.")
+		}
+	default:
+		panic(fmt.Sprintf("unknown Butterworth kind: %s", kind))
+	}
+	if order < 1 {
+		panic("Order must be at least 1.")
+	}
+	if low <= 0 || high >= sr/2 {
+		panic("Cutoff frequencies must be between 0 and half the sample rate.")
+	}
+}
+
+// ButterworthCoefficients returns the transfer function of Butterworth at the given sample rate.
+func ButterworthCoefficients(kind string, order int, low, high float64, sampleRate int) Coefficients {
+	checkButterworth(kind, order, low, high, float64(sampleRate))
+	return cascadeCoefficients(butterworthSections(kind, order, low, high, float64(sampleRate))...)
+}
+
+// Butterworth filters each channel of Wav with a Butterworth low pass, high pass, band pass
+// or band stop of any order, built from second order sections. Low and high pass cut off at low Hz,
+// band pass and band stop run from low to high Hz with twice the order.
+func (w *Wav) Butterworth(kind string, order int, low, high float64) {
+	checkButterworth(kind, order, low, high, float64(w.sampleRate))
+	w.filter(butterworthSections(kind, order, low, high, float64(w.sampleRate))...)
+}
@@ -0,0 +1,60 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestButterworthResponse(t *testing.T) {
+	cases := []struct {
+		kind      string
+		low, high float64
+		freqs     []float64
+		want      []float64
+	}{
+		{Lowpass, 4800, 0, []float64{0, 4800}, []float64{0, -3.0103}},
+		{Highpass, 4800, 0, []float64{4800, 24000}, []float64{-3.0103, 0}},
+		{Bandpass, 1000, 4000, []float64{1000, 2000, 4000}, []float64{-3.0103, 0, -3.0103}},
+		{Bandstop, 1000, 4000, []float64{0, 1000, 4000, 24000}, []float64{0, -3.0103, -3.0103, 0}},
+	}
+	for _, c := range cases {
+		for _, order := range []int{1, 2, 3, 4, 8} {
+			r := ButterworthCoefficients(c.kind, order, c.low, c.high, 48000).Response(48000, c.freqs)
+			for i, m := range r.Magnitude {
+				if math.Abs(m-c.want[i]) > 0.01 {
+					t.Errorf("%s order %d: got %f dB at %v Hz, wanted %f", c.kind, order, m, c.freqs[i], c.want[i])
+				}
+			}
+		}
+	}
+
+	// Each order adds 6 dB per octave well into the stopband and clear of Nyquist.
+	for _, order := range []int{2, 4, 8} {
+		r := ButterworthCoefficients(Lowpass, order, 500, 0, 192000).Response(192000, []float64{4000, 8000})
+		slope := r.Magnitude[0] - r.Magnitude[1]
+		if want := 6.02 * float64(order); math.Abs(slope-want) > 0.02*want {
+			t.Errorf("order %d: got %f dB per octave, wanted %f", order, slope, want)
+		}
+	}
+}
+
+func TestButterworthFilter(t *testing.T) {
+	track := newTestWav(48000, sine(48000, 1000, -6, 1), sine(48000, 12000, -6, 1))
+	track.Butterworth(Lowpass, 8, 4000, 0)
+	chans := track.Channels()
+	if got := track.toDBFS(rms(chans[0][4800:])) + 3.0103; math.Abs(got - -6) > 0.05 {
+		t.Errorf("got %f dBFS in the passband, wanted -6", got)
+	}
+	if got := track.toDBFS(rms(chans[1][4800:])) + 3.0103; got > -6-60 {
+		t.Errorf("got %f dBFS in the stopband, wanted below -66", got)
+	}
+}
+
+func TestButterworthEmptyBand(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("got no panic for a band pass with equal edges")
+		}
+	}()
+	ButterworthCoefficients(Bandpass, 2, 1000, 1000, 48000)
+}
@@ -0,0 +1,65 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChebyshevResponse(t *testing.T) {
+	// 0.5% ripple rises 0.0436 dB above DC, and the response falls to -3 dB at the cutoff.
+	ripple := -20 * math.Log10(1-0.005)
+	for _, poles := range []int{2, 4, 6, 8} {
+		c := ChebyshevCoefficients(4800, 0, 0.5, poles, 48000)
+		// The ripple reaches its full height before the response falls off to the cutoff.
+		r := c.Response(48000, Frequencies(500, 0, 4800, false))
+		peak := math.Inf(-1)
+		for _, m := range r.Magnitude {
+			peak = math.Max(peak, m)
+		}
+		if math.Abs(peak-ripple) > 0.002 {
+			t.Errorf("%d poles: got ripple of %f dB, wanted %f", poles, peak, ripple)
+		}
+		if got := c.Response(48000, []float64{4800}).Magnitude[0]; math.Abs(got - -3.01) > 0.1 {
+			t.Errorf("%d poles: got %f dB at the cutoff, wanted -3", poles, got)
+		}
+	}
+
+	// Type I falls faster than Butterworth, each pole pair steepening it.
+	lp := ChebyshevCoefficients(4800, 0, 0.5, 4, 48000).Response(48000, []float64{9600})
+	if got := lp.Magnitude[0]; got > -30 {
+		t.Errorf("got %f dB an octave above cutoff, wanted below -30", got)
+	}
+	hp := ChebyshevCoefficients(4800, 1, 0.5, 4, 48000).Response(48000, []float64{2400, 10000, 24000})
+	if hp.Magnitude[0] > -30 || math.Abs(hp.Magnitude[2]) > 0.001 || hp.Magnitude[1] < -0.001 || hp.Magnitude[1] > ripple+0.001 {
+		t.Errorf("got high pass %v dB at %v Hz", hp.Magnitude, hp.Frequencies)
+	}
+}
+
+func TestChebyshevFilter(t *testing.T) {
+	track := newTestWav(48000, sine(48000, 1000, -6, 1), sine(48000, 12000, -6, 1))
+	track.Chebyshev(4800, 0, 0.5, 6)
+	chans := track.Channels()
+	if got := track.toDBFS(rms(chans[0][4800:])) + 3.0103; math.Abs(got - -6) > 0.05 {
+		t.Errorf("got %f dBFS in the passband, wanted -6", got)
+	}
+	if got := track.toDBFS(rms(chans[1][4800:])) + 3.0103; got > -6-60 {
+		t.Errorf("got %f dBFS in the stopband, wanted below -66", got)
+	}
+}
+
+func TestChebyshevInvalid(t *testing.T) {
+	for _, c := range []struct {
+		fc     float64
+		ripple float64
+		poles  int
+	}{{4800, 0.5, 3}, {4800, 40, 4}, {30000, 0.5, 4}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%+v: got no panic", c)
+				}
+			}()
+			ChebyshevCoefficients(c.fc, 0, c.ripple, c.poles, 48000)
+		}()
+	}
+}
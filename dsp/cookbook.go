@@ -0,0 +1,131 @@
+package dsp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Kinds of Audio EQ Cookbook filter, named after Robert Bristow-Johnson's cookbook.
+// BPF has a constant 0 dB peak and BPFSkirt a constant skirt gain with a peak gain of Q.
+const (
+	LPF       = "lpf"
+	HPF       = "hpf"
+	BPF       = "bpf"
+	BPFSkirt  = "bpfskirt"
+	Notch     = "notch"
+	APF       = "apf"
+	PeakingEQ = "peak"
+	LowShelf  = "lowshelf"
+	HighShelf = "highshelf"
+)
+
+// Band is one Audio EQ Cookbook biquad at Frequency Hz. Its width is Bandwidth in octaves when set,
+// otherwise Slope for shelves when set, otherwise Q, which defaults to 1/sqrt(2).
+// Gain in dB only applies to peaking and shelving bands.
+type Band struct {
+	Type      string  `json:"type" yaml:"type"`
+	Frequency float64 `json:"frequency" yaml:"frequency"`
+	Gain      float64 `json:"gain,omitempty" yaml:"gain,omitempty"`
+	Q         float64 `json:"q,omitempty" yaml:"q,omitempty"`
+	Bandwidth float64 `json:"bandwidth,omitempty" yaml:"bandwidth,omitempty"`
+	Slope     float64 `json:"slope,omitempty" yaml:"slope,omitempty"`
+}
+
+// Validate returns an error for a band the cookbook cannot design at the given sample rate.
+func (b Band) Validate(sampleRate int) error {
+	switch b.Type {
+	case LPF, HPF, BPF, BPFSkirt, Notch, APF, PeakingEQ, LowShelf, HighShelf:
+	default:
+		return fmt.Errorf("unknown cookbook filter: %s", b.Type)
+	}
+	if nyquist := float64(sampleRate) / 2; b.Frequency <= 0 || b.Frequency >= nyquist {
+		return fmt.Errorf("%s frequency %g Hz must be between 0 and %g Hz", b.Type, b.Frequency, nyquist)
+	}
+	if b.Q < 0 || b.Bandwidth < 0 || b.Slope < 0 {
+		return fmt.Errorf("%s Q, bandwidth and slope must be positive", b.Type)
+	}
+	// Steep shelves overshoot, past 1/(1-2/(A+1/A)) the slope leaves no real alpha.
+	if A := math.Pow(10, b.Gain/40); b.Bandwidth == 0 && b.Slope > 0 && (b.Type == LowShelf || b.Type == HighShelf) &&
+		(A+1/A)*(1/b.Slope-1)+2 < 0 {
+		return fmt.Errorf("%s slope %g is too steep for %g dB, at most %.3f", b.Type, b.Slope, b.Gain, 1/(1-2/(A+1/A)))
+	}
+	return nil
+}
+
+// section returns the biquad of the band at sample rate sr.
+func (b Band) section(sr float64) biquad {
+	A := math.Pow(10, b.Gain/40)
+	w0 := 2 * math.Pi * b.Frequency / sr
+	cos, sin := math.Cos(w0), math.Sin(w0)
+	shelf := b.Type == LowShelf || b.Type == HighShelf
+
+	var alpha float64
+	switch {
+	case b.Bandwidth > 0:
+		alpha = sin * math.Sinh(math.Ln2/2*b.Bandwidth*w0/sin)
+	case b.Slope > 0 && shelf:
+		alpha = sin / 2 * math.Sqrt((A+1/A)*(1/b.Slope-1)+2)
+	default:
+		q := b.Q
+		if q == 0 {
+			q = math.Sqrt(0.5)
+		}
+		alpha = sin / (2 * q)
+	}
+
+	// Numerator b0..b2 and denominator a0..a2 as written in the cookbook.
+	var b0, b1, b2, a0, a1, a2 float64
+	switch b.Type {
+	case LPF:
+		b0, b1, b2 = (1-cos)/2, 1-cos, (1-cos)/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case HPF:
+		b0, b1, b2 = (1+cos)/2, -(1 + cos), (1+cos)/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case BPFSkirt:
+		b0, b1, b2 = sin/2, 0, -sin/2
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case BPF:
+		b0, b1, b2 = alpha, 0, -alpha
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case Notch:
+		b0, b1, b2 = 1, -2*cos, 1
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case APF:
+		b0, b1, b2 = 1-alpha, -2*cos, 1+alpha
+		a0, a1, a2 = 1+alpha, -2*cos, 1-alpha
+	case PeakingEQ:
+		b0, b1, b2 = 1+alpha*A, -2*cos, 1-alpha*A
+		a0, a1, a2 = 1+alpha/A, -2*cos, 1-alpha/A
+	case LowShelf:
+		r := 2 * math.Sqrt(A) * alpha
+		b0, b1, b2 = A*((A+1)-(A-1)*cos+r), 2*A*((A-1)-(A+1)*cos), A*((A+1)-(A-1)*cos-r)
+		a0, a1, a2 = (A+1)+(A-1)*cos+r, -2*((A-1)+(A+1)*cos), (A+1)+(A-1)*cos-r
+	case HighShelf:
+		r := 2 * math.Sqrt(A) * alpha
+		b0, b1, b2 = A*((A+1)+(A-1)*cos+r), -2*A*((A-1)+(A+1)*cos), A*((A+1)+(A-1)*cos-r)
+		a0, a1, a2 = (A+1)-(A-1)*cos+r, 2*((A-1)-(A+1)*cos), (A+1)-(A-1)*cos-r
+	}
+	return biquad{a1: b0 / a0, a2: b1 / a0, a3: b2 / a0, b1: a1 / a0, b2: a2 / a0}
+}
+
+// cookbookSections returns the biquads of the bands at sample rate sr, panicking on an invalid band.
+func cookbookSections(bands []Band, sr int) []biquad {
+	sections := make([]biquad, len(bands))
+	for i, b := range bands {
+		check(b.Validate(sr))
+		sections[i] = b.section(float64(sr))
+	}
+	return sections
+}
+
+// CookbookCoefficients returns the transfer function of the bands in series at the given sample rate.
+func CookbookCoefficients(sampleRate int, bands ...Band) Coefficients {
+	return cascadeCoefficients(cookbookSections(bands, sampleRate)...)
+}
+
+// Cookbook filters each channel of Wav with Audio EQ Cookbook biquads, running the bands in series
+// in a single pass so nothing is requantized between them.
+func (w *Wav) Cookbook(bands ...Band) {
+	w.filter(cookbookSections(bands, int(w.sampleRate))...)
+}
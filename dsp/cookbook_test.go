@@ -0,0 +1,90 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCookbookResponse(t *testing.T) {
+	cases := []struct {
+		band  Band
+		freqs []float64
+		want  []float64
+	}{
+		{Band{Type: LPF, Frequency: 1000}, []float64{0, 1000, 24000}, []float64{0, -3.0103, MinDBFS}},
+		{Band{Type: HPF, Frequency: 1000}, []float64{1000, 24000}, []float64{-3.0103, 0}},
+		{Band{Type: LPF, Frequency: 1000, Q: 2}, []float64{1000}, []float64{6.0206}},
+		{Band{Type: BPF, Frequency: 1000, Q: 4}, []float64{1000}, []float64{0}},
+		{Band{Type: BPFSkirt, Frequency: 1000, Q: 4}, []float64{1000}, []float64{12.0412}},
+		{Band{Type: Notch, Frequency: 1000}, []float64{0, 1000, 24000}, []float64{0, MinDBFS, 0}},
+		{Band{Type: APF, Frequency: 1000}, []float64{0, 300, 1000, 5000}, []float64{0, 0, 0, 0}},
+		{Band{Type: PeakingEQ, Frequency: 1000, Gain: 6, Bandwidth: 1}, []float64{0, 1000, 24000}, []float64{0, 6, 0}},
+		{Band{Type: PeakingEQ, Frequency: 1000, Gain: -9, Q: 1}, []float64{0, 1000}, []float64{0, -9}},
+		{Band{Type: LowShelf, Frequency: 1000, Gain: 6, Slope: 1}, []float64{0, 1000, 24000}, []float64{6, 3, 0}},
+		{Band{Type: HighShelf, Frequency: 1000, Gain: -6}, []float64{0, 1000, 24000}, []float64{0, -3, -6}},
+	}
+	for _, c := range cases {
+		r := CookbookCoefficients(48000, c.band).Response(48000, c.freqs)
+		for i, m := range r.Magnitude {
+			if math.Abs(m-c.want[i]) > 0.01 && !(c.want[i] == MinDBFS && m < -100) {
+				t.Errorf("%+v: got %f dB at %v Hz, wanted %f", c.band, m, c.freqs[i], c.want[i])
+			}
+		}
+	}
+
+	// Bandwidth in octaves puts the band edges of a band pass at -3 dB.
+	r := CookbookCoefficients(48000, Band{Type: BPF, Frequency: 1000, Bandwidth: 2}).Response(48000, []float64{500, 2000})
+	for i, m := range r.Magnitude {
+		if math.Abs(m - -3.0103) > 0.1 {
+			t.Errorf("got %f dB at %v Hz, wanted -3", m, r.Frequencies[i])
+		}
+	}
+
+	// The all pass turns the phase half way at its centre frequency.
+	r = CookbookCoefficients(48000, Band{Type: APF, Frequency: 1000}).Response(48000, []float64{1000})
+	if math.Abs(math.Abs(r.Phase[0])-math.Pi) > 1e-6 {
+		t.Errorf("got all pass phase %f at the centre, wanted pi", r.Phase[0])
+	}
+
+	// Bands in series add their gains.
+	both := CookbookCoefficients(48000, Band{Type: LowShelf, Frequency: 200, Gain: 4}, Band{Type: PeakingEQ, Frequency: 200, Gain: 3, Q: 1})
+	if got := both.Response(48000, []float64{0}).Magnitude[0]; math.Abs(got-4) > 0.01 {
+		t.Errorf("got %f dB at DC, wanted 4", got)
+	}
+}
+
+func TestCookbookFilter(t *testing.T) {
+	track := newTestWav(48000, sine(48000, 1000, -12, 1), sine(48000, 100, -12, 1))
+	track.Cookbook(Band{Type: PeakingEQ, Frequency: 1000, Gain: 6, Q: 2}, Band{Type: Notch, Frequency: 100, Q: 2})
+	chans := track.Channels()
+	if got := track.toDBFS(rms(chans[0][4800:])) + 3.0103; math.Abs(got - -6) > 0.05 {
+		t.Errorf("got %f dBFS at the peak, wanted -6", got)
+	}
+	if got := track.toDBFS(rms(chans[1][4800:])) + 3.0103; got > -12-60 {
+		t.Errorf("got %f dBFS at the notch, wanted below -72", got)
+	}
+}
+
+func TestCookbookValidate(t *testing.T) {
+	valid := []Band{
+		{Type: LowShelf, Frequency: 100, Gain: 12, Slope: 1},
+		{Type: HighShelf, Frequency: 8000, Gain: 12, Slope: 10, Bandwidth: 1},
+		{Type: PeakingEQ, Frequency: 1000, Gain: 12, Slope: 10},
+	}
+	for _, b := range valid {
+		if err := b.Validate(48000); err != nil {
+			t.Errorf("%+v: got %v", b, err)
+		}
+	}
+	invalid := []Band{
+		{Type: "shelf", Frequency: 100},
+		{Type: LPF, Frequency: 24000},
+		{Type: LPF, Frequency: 1000, Q: -1},
+		{Type: LowShelf, Frequency: 100, Gain: 12, Slope: 10},
+	}
+	for _, b := range invalid {
+		if err := b.Validate(48000); err == nil {
+			t.Errorf("%+v: got no error", b)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package dsp
+
+import (
+	"math"
+)
+
+// Difference is the result of a null test between two tracks.
+// Levels are relative to full scale, so tracks of different bit depths compare sample for sample.
+type Difference struct {
+	MaxAbs      float64 `json:"maxAbs"` // Largest difference as a fraction of full scale
+	MaxAbsDBFS  float64 `json:"maxAbsDBFS"`
+	RMSDBFS     float64 `json:"rmsDBFS"`     // RMS level of the residual
+	FirstSample int     `json:"firstSample"` // First frame that differs, -1 if none
+	FirstTime   float64 `json:"firstTime"`
+	Offset      int     `json:"offset"` // Frames the second track was delayed by before subtracting
+	Gain        float64 `json:"gain"`   // Gain in dB applied to the second track before subtracting
+	Identical   bool    `json:"identical"`
+	Frames      int     `json:"frames"` // Frames compared
+	Channels    int     `json:"channels"`
+}
+
+// normalized returns the channels of Wav scaled to the range -1 to 1.
+func (w *Wav) normalized() [][]float64 {
+	full := math.Pow(2, float64(w.bitsPerSample-1))
+	chans := w.Channels()
+	for _, ch := range chans {
+		for i := range ch {
+			ch[i] /= full
+		}
+	}
+	return chans
+}
+
+// Diff subtracts b from Wav after delaying b by offset frames and applying gain in dB, and
+// measures what is left. With align set, the offset within maxLag frames and the gain are
+// instead estimated from the tracks. Samples closer than tolerance, a fraction of full scale,
+// count as equal. Tracks are only identical with the same channels, sample rate and length. It returns the difference and the residual in the format of Wav.
+func (w *Wav) Diff(b *Wav, offset int, gain float64, align bool, maxLag int, tolerance float64) (Difference, *Wav) {
+	x, y := w.normalized(), b.normalized()
+	nch := int(math.Min(float64(len(x)), float64(len(y))))
+	if align {
+		offset = lag(x[0], y[0], maxLag, false)
+	}
+
+	// Frames of both tracks that overlap once b is shifted.
+	frames := int(math.Min(float64(len(x[0])), float64(len(y[0])-offset)))
+	from := int(math.Max(0, float64(-offset)))
+	if frames < from {
+		frames = from
+	}
+
+	g := math.Pow(10, gain/20)
+	if align {
+		// Least squares gain from b to Wav.
+		var xy, yy float64
+		for c := 0; c < nch; c++ {
+			for i := from; i < frames; i++ {
+				xy += x[c][i] * y[c][i+offset]
+				yy += y[c][i+offset] * y[c][i+offset]
+			}
+		}
+		if yy > 0 && xy > 0 {
+			g = xy / yy
+		}
+		gain = 20 * math.Log10(g)
+	}
+
+	d := Difference{FirstSample: -1, Offset: offset, Gain: gain, Frames: frames - from, Channels: nch}
+	residual := make([][]float64, nch)
+	var sum float64
+	full := math.Pow(2, float64(w.bitsPerSample-1))
+	for c := 0; c < nch; c++ {
+		residual[c] = make([]float64, len(x[c]))
+		for i := from; i < frames; i++ {
+			r := x[c][i] - g*y[c][i+offset]
+			residual[c][i] = w.clip(r * full)
+			sum += r * r
+			if math.Abs(r) > d.MaxAbs {
+				d.MaxAbs = math.Abs(r)
+			}
+			if math.Abs(r) > tolerance && (d.FirstSample < 0 || i < d.FirstSample) {
+				d.FirstSample = i
+			}
+		}
+	}
+	d.MaxAbsDBFS = math.Max(20*math.Log10(d.MaxAbs), MinDBFS)
+	if n := nch * (frames - from); n > 0 {
+		d.RMSDBFS = math.Max(20*math.Log10(math.Sqrt(sum/float64(n))), MinDBFS)
+	} else {
+		d.RMSDBFS = MinDBFS
+	}
+	if d.FirstSample >= 0 {
+		d.FirstTime = float64(d.FirstSample) / float64(w.sampleRate)
+	}
+	d.Identical = d.FirstSample < 0 && len(x[0]) == len(y[0]) && offset == 0 &&
+		len(x) == len(y) && w.sampleRate == b.sampleRate
+
+	res := *w
+	res.data = nil
+	res.cues = nil
+	res.SetChannels(residual)
+	return d, &res
+}
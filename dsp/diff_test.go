@@ -0,0 +1,92 @@
+package dsp
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestSampleRoundTrip(t *testing.T) {
+	for _, bits := range []uint16{8, 16, 24, 32} {
+		full := math.Pow(2, float64(bits-1))
+		track := newTestWav(8000, []float64{0, 1, -1, full - 1, -full})
+		track.bitsPerSample = bits
+		track.updateHeader()
+		copy(track.chunkID[:], "RIFF")
+		copy(track.format[:], "WAVE")
+		copy(track.subchunk1ID[:], "fmt ")
+		copy(track.subchunk2ID[:], "data")
+		var b bytes.Buffer
+		track.Write(&b)
+
+		read := NewWav()
+		if err := read.Read(&b); err != nil {
+			t.Fatal(err)
+		}
+		if !floatSliceEqual(read.data, track.data) {
+			t.Errorf("%d-bit: got %f, wanted %f", bits, read.data, track.data)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	x := sine(8000, 440, -6, 1)
+	a := newTestWav(8000, x)
+
+	// The same tone at 24 bits nulls against the 16-bit one.
+	y := make([]float64, len(x))
+	for i := range x {
+		y[i] = x[i] * 256
+	}
+	b := newTestWav(8000, y)
+	b.bitsPerSample = 24
+	if d, _ := a.Diff(b, 0, 0, false, 0, 1e-9); !d.Identical || d.MaxAbsDBFS != MinDBFS {
+		t.Errorf("got %+v, wanted identical", d)
+	}
+
+	// Delayed by 100 samples and 6 dB quieter, it only nulls once aligned.
+	z := append(make([]float64, 100), x...)
+	for i := range z {
+		z[i] /= 2
+	}
+	c := newTestWav(8000, z)
+	if d, _ := a.Diff(c, 0, 0, false, 0, 1e-4); d.FirstSample < 0 || d.FirstSample > 1 {
+		t.Errorf("got first difference at %d unaligned, wanted 1", d.FirstSample)
+	}
+	d, residual := a.Diff(c, 0, 0, true, 400, 1e-4)
+	if d.Offset != 100 || math.Abs(d.Gain-6.02) > 0.01 || d.FirstSample >= 0 {
+		t.Errorf("got %+v, wanted offset 100 and gain 6.02 dB", d)
+	}
+	if got := residual.peak(); got > 1 {
+		t.Errorf("got residual peak %f, wanted silence", got)
+	}
+}
+
+func TestDiffFormat(t *testing.T) {
+	x := sine(8000, 440, -6, 0.1)
+	a := newTestWav(8000, x)
+	if d, _ := a.Diff(newTestWav(16000, x), 0, 0, false, 0, 0); d.Identical {
+		t.Error("got identical with a different sample rate")
+	}
+	if d, _ := a.Diff(newTestWav(8000, x, x), 0, 0, false, 0, 0); d.Identical {
+		t.Error("got identical with a different channel count")
+	}
+}
+
+func TestWriteClips(t *testing.T) {
+	track := newTestWav(8000, []float64{40000, -40000, 1.6, -1.6})
+	copy(track.chunkID[:], "RIFF")
+	copy(track.format[:], "WAVE")
+	copy(track.subchunk1ID[:], "fmt ")
+	copy(track.subchunk2ID[:], "data")
+	var b bytes.Buffer
+	track.Write(&b)
+
+	read := NewWav()
+	if err := read.Read(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := read.data, []float64{32767, -32768, 2, -2}; !floatSliceEqual(got, want) {
+		t.Errorf("got %f, wanted %f", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package dsp
+
+import (
+	"math"
+)
+
+// Distortion holds the harmonic distortion and noise of a sine test tone.
+// THD and THD+N are given both in percent and in dB relative to the fundamental.
+type Distortion struct {
+	Fundamental float64   `json:"fundamental"` // Frequency in Hz
+	Level       float64   `json:"level"`       // Level of the fundamental in dBFS
+	THD         float64   `json:"thd"`
+	THDDB       float64   `json:"thdDB"`
+	THDN        float64   `json:"thdn"`
+	THDNDB      float64   `json:"thdnDB"`
+	SINAD       float64   `json:"sinad"`
+	Harmonics   []float64 `json:"harmonics"` // Level of each harmonic from the 2nd in dB relative to the fundamental
+}
+
+// distortionBins is the half width in bins of the band summed around each tone,
+// covering the main lobe of the Blackman-Harris window.
+const distortionBins = 8
+
+// blackmanHarris7 returns a 7-term Blackman-Harris window of length n. Its sidelobes are about 180 dB down,
+// so leakage from the fundamental stays well below the quantization noise of a 24-bit tone.
+func blackmanHarris7(n int) []float64 {
+	a := []float64{0.27105140069342, 0.43329793923448, 0.21812299954311, 0.06592544638803,
+		0.01081174209837, 0.00077658482522, 0.00001388721735}
+	w := make([]float64, n)
+	for i := range w {
+		t := 2 * math.Pi * float64(i) / float64(n)
+		for j, v := range a {
+			w[i] += math.Pow(-1, float64(j)) * v * math.Cos(float64(j)*t)
+		}
+	}
+	return w
+}
+
+// NewSine returns a 16-bit track of a sine at freq Hz and level dBFS on every channel.
+func NewSine(sampleRate, channels int, freq, level, seconds float64) *Wav {
+	w := NewWav()
+	copy(w.chunkID[:], "RIFF")
+	copy(w.format[:], "WAVE")
+	copy(w.subchunk1ID[:], "fmt ")
+	copy(w.subchunk2ID[:], "data")
+	w.subchunk1Size = 16
+	w.audioFormat = 1
+	w.sampleRate = uint32(sampleRate)
+	w.bitsPerSample = 16
+	amp := w.fromDBFS(level)
+	x := make([]float64, int(float64(sampleRate)*seconds))
+	for i := range x {
+		x[i] = math.Round(amp * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate)))
+	}
+	chans := make([][]float64, channels)
+	for c := range chans {
+		chans[c] = x
+	}
+	w.SetChannels(chans)
+	return w
+}
+
+// Distortion measures the distortion of a sine test tone in Wav from its spectrum averaged over
+// half overlapping frames of the given size with a 7-term Blackman-Harris window.
+// The fundamental is the strongest component, THD sums up to the given number of harmonics
+// below Nyquist, and THD+N and SINAD take everything other than the fundamental and DC
+// as distortion and noise.
+func (w *Wav) Distortion(size, harmonics int) Distortion {
+	win := blackmanHarris7(size)
+	// Scale so a full scale sine reads 0 dB, as in Spectrum.
+	var scale float64
+	for _, v := range win {
+		scale += v
+	}
+	scale *= math.Pow(2, float64(w.bitsPerSample-1)) / 2
+
+	power := make([]float64, size/2+1)
+	var frames float64
+	for _, ch := range w.Channels() {
+		for i := 0; i == 0 || i+size <= len(ch); i += size / 2 {
+			frame := ch[i:int(math.Min(float64(i+size), float64(len(ch))))]
+			for k, p := range powerSpectrum(frame, win, scale) {
+				power[k] += p
+			}
+			frames++
+		}
+	}
+	s := Spectrum{Frequencies: make([]float64, len(power)), Magnitudes: make([]float64, len(power))}
+	for k := range power {
+		power[k] /= frames
+		s.Frequencies[k] = float64(k) * float64(w.sampleRate) / float64(size)
+		s.Magnitudes[k] = math.Max(10*math.Log10(power[k]), MinDBFS)
+	}
+	band := func(k int) float64 {
+		var sum float64
+		for j := int(math.Max(0, float64(k-distortionBins))); j <= k+distortionBins && j < len(power); j++ {
+			sum += power[j]
+		}
+		return sum
+	}
+
+	var d Distortion
+	k0 := distortionBins + 1
+	for k := k0; k < len(power); k++ {
+		if power[k] > power[k0] {
+			k0 = k
+		}
+	}
+	if k0 >= len(power) {
+		return d
+	}
+	// The fundamental frequency and level are refined by the interpolation of Peaks.
+	d.Fundamental, d.Level = s.Frequencies[k0], s.Magnitudes[k0]
+	if k0+1 < len(power) {
+		peak := Spectrum{s.Frequencies[k0-1 : k0+2], s.Magnitudes[k0-1 : k0+2]}.Peaks(1)
+		if len(peak) > 0 {
+			d.Fundamental, d.Level = peak[0].Frequency, peak[0].Magnitude
+		}
+	}
+	fundamental := band(k0)
+
+	binWidth := s.Frequencies[1] - s.Frequencies[0]
+	var harmonicPower float64
+	for h := 2; h <= harmonics; h++ {
+		center := int(math.Round(float64(h) * d.Fundamental / binWidth))
+		if center+distortionBins >= len(power) {
+			break
+		}
+		// The harmonic is the strongest bin near where it is expected.
+		kh := center
+		for j := center - distortionBins; j <= center+distortionBins; j++ {
+			if power[j] > power[kh] {
+				kh = j
+			}
+		}
+		p := band(kh)
+		harmonicPower += p
+		d.Harmonics = append(d.Harmonics, math.Max(10*math.Log10(p/fundamental), MinDBFS))
+	}
+
+	var total float64
+	for k := distortionBins + 1; k < len(power); k++ {
+		total += power[k]
+	}
+	rest := math.Max(0, total-fundamental)
+	d.THD = 100 * math.Sqrt(harmonicPower/fundamental)
+	d.THDDB = math.Max(10*math.Log10(harmonicPower/fundamental), MinDBFS)
+	d.THDN = 100 * math.Sqrt(rest/fundamental)
+	d.THDNDB = math.Max(10*math.Log10(rest/fundamental), MinDBFS)
+	d.SINAD = math.Min(10*math.Log10(total/rest), -MinDBFS)
+	return d
+}
@@ -0,0 +1,36 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistortion(t *testing.T) {
+	// 1 kHz at -6 dBFS with 2nd and 3rd harmonics each 40 dB down, for a THD of 1.414%.
+	x := sine(48000, 1000, -6, 1)
+	h2 := sine(48000, 2000, -46, 1)
+	h3 := sine(48000, 3000, -46, 1)
+	for i := range x {
+		x[i] += h2[i] + h3[i]
+	}
+	d := newTestWav(48000, x).Distortion(8192, 5)
+
+	if math.Abs(d.Fundamental-1000) > 0.5 || math.Abs(d.Level - -6.02) > 0.05 {
+		t.Errorf("got fundamental %f Hz at %f dBFS, wanted 1000 Hz at -6.02", d.Fundamental, d.Level)
+	}
+	if math.Abs(d.THD-1.414) > 0.01 || math.Abs(d.THDDB - -36.99) > 0.05 {
+		t.Errorf("got THD %f%% (%f dB), wanted 1.414%% (-36.99 dB)", d.THD, d.THDDB)
+	}
+	if math.Abs(d.Harmonics[0] - -40) > 0.05 || math.Abs(d.Harmonics[1] - -40) > 0.05 || d.Harmonics[2] > -90 {
+		t.Errorf("got harmonics %v dB, wanted -40, -40 and nothing", d.Harmonics)
+	}
+	// With nothing else in the signal, THD+N is the THD and SINAD its inverse.
+	if math.Abs(d.THDNDB-d.THDDB) > 0.05 || math.Abs(d.SINAD-36.99) > 0.05 {
+		t.Errorf("got THD+N %f dB and SINAD %f dB, wanted -36.99 and 36.99", d.THDNDB, d.SINAD)
+	}
+
+	// A clean 16-bit tone is limited by quantization noise, about 98 dB below full scale.
+	if d := NewSine(48000, 1, 1000, 0, 1).Distortion(8192, 5); d.SINAD < 90 || d.THDDB > -100 {
+		t.Errorf("got SINAD %f dB and THD %f dB from a clean tone", d.SINAD, d.THDDB)
+	}
+}
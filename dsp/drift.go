@@ -0,0 +1,153 @@
+package dsp
+
+import (
+	"math"
+)
+
+// driftConfidence is the lowest normalized correlation peak of a window used in the drift fit.
+const driftConfidence = 0.3
+
+// Resampling interpolates between samples with a Blackman windowed sinc of resampleTaps
+// samples either side, tabulated at resamplePhases fractional positions.
+const (
+	resampleTaps   = 16
+	resamplePhases = 512
+)
+
+// resampleTable holds the interpolation kernel at every phase, and one past the last
+// so neighbouring phases can be interpolated.
+var resampleTable = func() [][]float64 {
+	table := make([][]float64, resamplePhases+1)
+	for p := range table {
+		frac := float64(p) / resamplePhases
+		table[p] = make([]float64, 2*resampleTaps)
+		var sum float64
+		for j := range table[p] {
+			t := frac - float64(j-resampleTaps+1)
+			sinc := 1.0
+			if t != 0 {
+				sinc = math.Sin(math.Pi*t) / (math.Pi * t)
+			}
+			u := math.Pi * t / resampleTaps
+			table[p][j] = sinc * (0.42 + 0.5*math.Cos(u) + 0.08*math.Cos(2*u))
+			sum += table[p][j]
+		}
+		// Unity gain at DC for every phase.
+		for j := range table[p] {
+			table[p][j] /= sum
+		}
+	}
+	return table
+}()
+
+// interpolateAt returns x at a fractional position, band limited, with silence outside of x.
+func interpolateAt(x []float64, pos float64) float64 {
+	n := int(math.Floor(pos))
+	phase := (pos - float64(n)) * resamplePhases
+	p := int(phase)
+	mix := phase - float64(p)
+	var y float64
+	for j := 0; j < 2*resampleTaps; j++ {
+		i := n + j - resampleTaps + 1
+		if i < 0 || i >= len(x) {
+			continue
+		}
+		y += x[i] * (resampleTable[p][j] + mix*(resampleTable[p+1][j]-resampleTable[p][j]))
+	}
+	return y
+}
+
+// DriftPoint is the lag in seconds of the second track measured over the window starting at Time.
+type DriftPoint struct {
+	Time       float64 `json:"time"`
+	Lag        float64 `json:"lag"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Drift is the lag of one track behind another modelled as Offset + Rate*t seconds at time t.
+// Rate is dimensionless, 1e-6 is a drift of one ppm.
+type Drift struct {
+	Offset float64      `json:"offset"`
+	Rate   float64      `json:"rate"`
+	Points []DriftPoint `json:"points"`
+}
+
+// Drift measures the lag of b behind Wav over windows of the given length in seconds every hop seconds,
+// searching within maxLag seconds either way, and fits a line through the lags weighted by how well
+// each window correlated. Windows with a normalized correlation below 0.3 are left out of the fit.
+func (w *Wav) Drift(b *Wav, window, hop, maxLag float64) Drift {
+	sr := float64(w.sampleRate)
+	x, y := w.mono(), b.mono()
+	size := int(math.Max(1, window*sr))
+	step := int(math.Max(1, hop*sr))
+	search := int(maxLag * sr)
+
+	var d Drift
+	for i := 0; i+size <= len(x) && i+size <= len(y); i += step {
+		a, c := x[i:i+size], y[i:i+size]
+		r := crossCorrelation(a, c, false)
+		limit := int(math.Min(float64(search), float64(len(r)/2-1)))
+		best, peak := math.Inf(-1), 0
+		for l := -limit; l <= limit; l++ {
+			if v := r[(l+len(r))%len(r)]; v > best {
+				best, peak = v, l
+			}
+		}
+		// Parabolic interpolation between the correlations either side of the peak.
+		at := func(l int) float64 { return r[(l+len(r))%len(r)] }
+		frac := 0.0
+		if den := at(peak-1) - 2*best + at(peak+1); den < 0 {
+			frac = 0.5 * (at(peak-1) - at(peak+1)) / den
+		}
+		var ea, ec float64
+		for j := range a {
+			ea += a[j] * a[j]
+			ec += c[j] * c[j]
+		}
+		p := DriftPoint{Time: float64(i) / sr, Lag: (float64(peak) + frac) / sr}
+		if ea > 0 && ec > 0 {
+			p.Confidence = math.Max(0, best/math.Sqrt(ea*ec))
+		}
+		d.Points = append(d.Points, p)
+	}
+
+	// Weighted least squares fit of the lag at the centre of each window.
+	var sw, st, sl, stt, stl float64
+	for _, p := range d.Points {
+		if p.Confidence < driftConfidence {
+			continue
+		}
+		wt := p.Confidence * p.Confidence
+		t := p.Time + window/2
+		sw += wt
+		st += wt * t
+		sl += wt * p.Lag
+		stt += wt * t * t
+		stl += wt * t * p.Lag
+	}
+	if sw == 0 {
+		return d
+	}
+	d.Offset = sl / sw
+	if den := sw*stt - st*st; den > 1e-12*sw*sw {
+		d.Rate = (sw*stl - st*sl) / den
+		d.Offset = (sl - d.Rate*st) / sw
+	}
+	return d
+}
+
+// Resync resamples Wav so that frame n of the result is read from offset seconds plus n*(1+rate) frames
+// of the original, undoing a lag of offset + rate*t seconds. It keeps as many frames as the original covers.
+func (w *Wav) Resync(offset, rate float64) {
+	sr := float64(w.sampleRate)
+	chans := w.Channels()
+	n := int(math.Max(0, math.Ceil((float64(len(chans[0]))-offset*sr)/(1+rate))))
+	for c, ch := range chans {
+		out := make([]float64, n)
+		for i := range out {
+			out[i] = w.clip(math.Round(interpolateAt(ch, offset*sr+float64(i)*(1+rate))))
+		}
+		chans[c] = out
+	}
+	w.SetChannels(chans)
+}
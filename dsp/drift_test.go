@@ -0,0 +1,45 @@
+package dsp
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestResyncInterpolates(t *testing.T) {
+	// Reading a band limited tone half a sample late matches the tone shifted by half a sample.
+	x := sine(8000, 440, -6, 1)
+	track := newTestWav(8000, x)
+	track.Resync(0.5/8000, 0)
+	want := 32768 * math.Pow(10, -6.0/20) * math.Sin(2*math.Pi*440*1000.5/8000)
+	if got := track.data[1000]; math.Abs(got-want) > 1 {
+		t.Errorf("got %f, wanted %f", got, want)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	x := make([]float64, 8000*20)
+	for i := range x {
+		x[i] = 3000 * rng.NormFloat64()
+	}
+	// Band limited well below Nyquist, where resampling is transparent.
+	lp := biquadSection(1500, 0, 8000)
+	x = cascade(x, lp, lp, lp, lp)
+	a := newTestWav(8000, x)
+
+	// b lags a by 10ms plus 100ppm, so b at 10ms + 1.0001t holds a at t.
+	offset, rate := 0.01, 1e-4
+	b := newTestWav(8000, x)
+	b.Resync(-offset/(1+rate), 1/(1+rate)-1)
+
+	d := a.Drift(b, 1, 2, 0.1)
+	if math.Abs(d.Offset-offset) > 2e-5 || math.Abs(d.Rate-rate) > 2e-6 {
+		t.Errorf("got offset %f and rate %g, wanted %f and %g", d.Offset, d.Rate, offset, rate)
+	}
+
+	b.Resync(d.Offset, d.Rate)
+	if q := a.Quality(b, 512); q.SNR < 40 {
+		t.Errorf("got SNR %f dB after correcting, wanted above 40", q.SNR)
+	}
+}
@@ -1,9 +1,12 @@
 package dsp
 
 import (
+	"bufio"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"os"
 )
@@ -46,6 +49,7 @@ type Wav struct {
 	subchunk2ID   [4]byte
 	subchunk2Size uint32
 	data          []float64
+	cues          []uint32 // Cue point sample offsets
 	// Derived fields
 	NumSamples uint32
 	SampleSize uint16
@@ -58,28 +62,114 @@ func NewWav() *Wav {
 }
 
 // Read reads binary data from an io.Reader into Wav.
-func (w *Wav) Read(r io.Reader) {
+// Chunks other than fmt, data and cue are skipped. Format extensions are dropped,
+// so WAVE_FORMAT_EXTENSIBLE PCM is read as plain PCM. A missing fmt chunk, or one after
+// the data chunk, is an error, as are a malformed fmt chunk and any format other than 8 to 32-bit integer PCM.
+func (w *Wav) Read(r io.Reader) error {
 	binary.Read(r, binary.BigEndian, &w.chunkID)
 	binary.Read(r, binary.LittleEndian, &w.chunkSize)
 	binary.Read(r, binary.BigEndian, &w.format)
-	binary.Read(r, binary.BigEndian, &w.subchunk1ID)
-	binary.Read(r, binary.LittleEndian, &w.subchunk1Size)
-	binary.Read(r, binary.LittleEndian, &w.audioFormat)
-	binary.Read(r, binary.LittleEndian, &w.numChannels)
-	binary.Read(r, binary.LittleEndian, &w.sampleRate)
-	binary.Read(r, binary.LittleEndian, &w.byteRate)
-	binary.Read(r, binary.LittleEndian, &w.blockAlign)
-	binary.Read(r, binary.LittleEndian, &w.bitsPerSample)
-	binary.Read(r, binary.BigEndian, &w.subchunk2ID)
-	binary.Read(r, binary.LittleEndian, &w.subchunk2Size)
-	w.NumSamples = (8 * w.subchunk2Size) / uint32(w.bitsPerSample)
-	w.SampleSize = (w.numChannels * w.bitsPerSample) / 8
-	w.Duration = float64(w.subchunk2Size) / float64(w.byteRate)
-	for i := 0; i < int(w.NumSamples); i++ {
-		x := make([]byte, w.bitsPerSample/8)
-		binary.Read(r, binary.LittleEndian, &x)
-		smp := float64(int16(binary.LittleEndian.Uint16(x)))
-		w.data = append(w.data, smp)
+	for {
+		var id [4]byte
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &id); err != nil {
+			break
+		}
+		binary.Read(r, binary.LittleEndian, &size)
+		switch string(id[:]) {
+		case "fmt ":
+			if size < 16 {
+				return fmt.Errorf("fmt chunk of %d bytes is too short", size)
+			}
+			w.subchunk1ID = id
+			binary.Read(r, binary.LittleEndian, &w.audioFormat)
+			binary.Read(r, binary.LittleEndian, &w.numChannels)
+			binary.Read(r, binary.LittleEndian, &w.sampleRate)
+			binary.Read(r, binary.LittleEndian, &w.byteRate)
+			binary.Read(r, binary.LittleEndian, &w.blockAlign)
+			binary.Read(r, binary.LittleEndian, &w.bitsPerSample)
+			extra := make([]byte, int64(size)-16)
+			io.ReadFull(r, extra)
+			w.subchunk1Size = 16
+			if w.audioFormat == 0xFFFE && len(extra) >= 10 {
+				// The sub format GUID starts with the format code, after the extension size,
+				// valid bits and channel mask.
+				w.audioFormat = binary.LittleEndian.Uint16(extra[8:10])
+			}
+			if w.audioFormat != 1 {
+				return fmt.Errorf("unsupported audio format %d, only PCM is read", w.audioFormat)
+			}
+			if w.numChannels == 0 {
+				return errors.New("fmt chunk has no channels")
+			}
+			if w.bitsPerSample == 0 || w.bitsPerSample > 32 || w.bitsPerSample%8 != 0 {
+				return fmt.Errorf("unsupported bits per sample: %d", w.bitsPerSample)
+			}
+		case "data":
+			if w.bitsPerSample == 0 {
+				return errors.New("data chunk before fmt chunk")
+			}
+			w.subchunk2ID = id
+			w.subchunk2Size = size
+			w.NumSamples = (8 * w.subchunk2Size) / uint32(w.bitsPerSample)
+			for i := 0; i < int(w.NumSamples); i++ {
+				x := make([]byte, w.bitsPerSample/8)
+				binary.Read(r, binary.LittleEndian, &x)
+				w.data = append(w.data, decodeSample(x))
+			}
+		case "cue ":
+			var n uint32
+			binary.Read(r, binary.LittleEndian, &n)
+			for i := 0; i < int(n); i++ {
+				var point struct {
+					ID, Position uint32
+					ChunkID      [4]byte
+					ChunkStart   uint32
+					BlockStart   uint32
+					SampleOffset uint32
+				}
+				binary.Read(r, binary.LittleEndian, &point)
+				w.cues = append(w.cues, point.SampleOffset)
+			}
+			io.CopyN(ioutil.Discard, r, int64(size)-4-24*int64(n))
+		default:
+			io.CopyN(ioutil.Discard, r, int64(size))
+		}
+		if size%2 == 1 {
+			io.CopyN(ioutil.Discard, r, 1)
+		}
+	}
+	if string(w.subchunk1ID[:]) != "fmt " {
+		return errors.New("missing fmt chunk")
+	}
+	// Only the fmt and data chunks are written back.
+	w.updateHeader()
+	return nil
+}
+
+// decodeSample returns the value of a little endian PCM sample of 1 to 4 bytes.
+// 8-bit samples are unsigned and centered on 128, wider ones are signed.
+func decodeSample(x []byte) float64 {
+	if len(x) == 1 {
+		return float64(int(x[0]) - 128)
+	}
+	var v int32
+	for i, b := range x {
+		v |= int32(b) << (8 * uint(i))
+	}
+	// Sign extend from the top byte.
+	shift := uint(32 - 8*len(x))
+	return float64(v << shift >> shift)
+}
+
+// encodeSample writes a sample value into x as little endian PCM, the inverse of decodeSample.
+func encodeSample(x []byte, smp float64) {
+	v := int32(smp)
+	if len(x) == 1 {
+		v += 128
+	}
+	for i := range x {
+		x[i] = byte(v >> (8 * uint(i)))
 	}
 }
 
@@ -88,7 +178,7 @@ func (w *Wav) ReadFile(path string) {
 	f, err := os.Open(path)
 	check(err)
 	defer f.Close()
-	w.Read(f)
+	check(w.Read(bufio.NewReader(f)))
 }
 
 // Write writes Wav data into an io.Writer as binary.
@@ -108,7 +198,7 @@ func (w *Wav) Write(r io.Writer) {
 	binary.Write(r, binary.LittleEndian, w.subchunk2Size)
 	for i := 0; i < int(w.NumSamples); i++ {
 		signal := make([]byte, w.bitsPerSample/8)
-		binary.LittleEndian.PutUint16(signal, uint16(w.data[i]))
+		encodeSample(signal, w.clip(math.Round(w.data[i])))
 		binary.Write(r, binary.LittleEndian, signal)
 	}
 }
@@ -118,7 +208,9 @@ func (w *Wav) WriteFile(path string) {
 	f, err := os.Create(path)
 	check(err)
 	defer f.Close()
-	w.Write(f)
+	b := bufio.NewWriter(f)
+	w.Write(b)
+	check(b.Flush())
 }
 
 // DumpHeader prints Wav header information.
@@ -144,6 +236,90 @@ func (w *Wav) DumpHeader(more bool) {
 	}
 }
 
+// NumChannels returns the number of interleaved channels in Wav.
+func (w *Wav) NumChannels() int {
+	return int(w.numChannels)
+}
+
+// SampleRate returns the sample rate of Wav in Hz.
+func (w *Wav) SampleRate() int {
+	return int(w.sampleRate)
+}
+
+// BitsPerSample returns the bit depth of Wav.
+func (w *Wav) BitsPerSample() int {
+	return int(w.bitsPerSample)
+}
+
+// Channels returns a deinterleaved copy of each channel's samples.
+func (w *Wav) Channels() [][]float64 {
+	nch := int(w.numChannels)
+	if nch == 0 {
+		nch = 1
+	}
+	n := len(w.data) / nch
+	chans := make([][]float64, nch)
+	for c := range chans {
+		chans[c] = make([]float64, n)
+		for i := 0; i < n; i++ {
+			chans[c][i] = w.data[i*nch+c]
+		}
+	}
+	return chans
+}
+
+// mono returns the average of all channels of Wav.
+func (w *Wav) mono() []float64 {
+	chans := w.Channels()
+	x := make([]float64, len(chans[0]))
+	for _, ch := range chans {
+		for i, v := range ch {
+			x[i] += v / float64(len(chans))
+		}
+	}
+	return x
+}
+
+// SetChannels interleaves the given channels into Wav and updates the header to match.
+// All channels are expected to be the same length.
+func (w *Wav) SetChannels(chans [][]float64) {
+	n := 0
+	if len(chans) > 0 {
+		n = len(chans[0])
+	}
+	data := make([]float64, n*len(chans))
+	for c, ch := range chans {
+		for i := 0; i < n; i++ {
+			data[i*len(chans)+c] = ch[i]
+		}
+	}
+	w.numChannels = uint16(len(chans))
+	w.data = data
+	w.updateHeader()
+}
+
+// updateHeader recomputes the size fields of the header from the sample data.
+func (w *Wav) updateHeader() {
+	w.NumSamples = uint32(len(w.data))
+	w.blockAlign = w.numChannels * w.bitsPerSample / 8
+	w.byteRate = w.sampleRate * uint32(w.blockAlign)
+	w.subchunk2Size = w.NumSamples * uint32(w.bitsPerSample) / 8
+	w.chunkSize = 20 + w.subchunk1Size + w.subchunk2Size
+	w.SampleSize = w.blockAlign
+	w.Duration = float64(w.subchunk2Size) / float64(w.byteRate)
+}
+
+// clip limits x to the range representable by the sample size of Wav.
+func (w *Wav) clip(x float64) float64 {
+	max := math.Pow(2, float64(w.bitsPerSample-1))
+	if x > max-1 {
+		return max - 1
+	} else if x < -max {
+		return -max
+	}
+	return x
+}
+
 // ReconSignal reconstructs signal data into Wav from a given inverse DFT.
 func (w *Wav) ReconSignal(idft []complex128) {
 	if len(w.data) < len(idft) {
@@ -161,8 +337,12 @@ func (w *Wav) ReconSignal(idft []complex128) {
 	}
 }
 
-// Mix mixes two tracks into one.
+// Mix mixes two tracks into one. Both tracks must share their sample rate, channels and bit depth.
 func (w *Wav) Mix(t1 *Wav, t2 *Wav) {
+	if t1.sampleRate != t2.sampleRate || t1.numChannels != t2.numChannels || t1.bitsPerSample != t2.bitsPerSample {
+		panic(fmt.Sprintf("cannot mix %d Hz, %d channel, %d-bit with %d Hz, %d channel, %d-bit",
+			t1.sampleRate, t1.numChannels, t1.bitsPerSample, t2.sampleRate, t2.numChannels, t2.bitsPerSample))
+	}
 	var longerTrack, shorterTrack *Wav
 	if t1.NumSamples >= t2.NumSamples {
 		longerTrack = t1
@@ -179,18 +359,25 @@ func (w *Wav) Mix(t1 *Wav, t2 *Wav) {
 		} else {
 			x = longerTrack.data[i]
 		}
-		if x > 32767 {
-			x = 32767
-		} else if x < -32768 {
-			x = -32768
-		}
-		w.data[i] = x
+		w.data[i] = w.clip(x)
 	}
 }
 
-// Normalize normalizes a track according to the desired peak in dBFS.
-func (w *Wav) Normalize(desiredPeak float64) {
-	base := math.Pow(2, float64(w.bitsPerSample-1)) * math.Pow(10, (desiredPeak/20))
+// MinDBFS is the level reported for digital silence in place of -Inf.
+const MinDBFS = -200.0
+
+// toDBFS converts a sample magnitude into dB relative to the full scale of Wav.
+func (w *Wav) toDBFS(x float64) float64 {
+	return math.Max(20*math.Log10(x/math.Pow(2, float64(w.bitsPerSample-1))), MinDBFS)
+}
+
+// fromDBFS converts a level in dB relative to full scale into a sample magnitude of Wav.
+func (w *Wav) fromDBFS(db float64) float64 {
+	return math.Pow(2, float64(w.bitsPerSample-1)) * math.Pow(10, db/20)
+}
+
+// peak returns the largest sample magnitude in Wav.
+func (w *Wav) peak() float64 {
 	var peak float64 = 0
 	for i := 0; i < int(w.NumSamples); i++ {
 		x := math.Abs(w.data[i])
@@ -198,22 +385,32 @@ func (w *Wav) Normalize(desiredPeak float64) {
 			peak = x
 		}
 	}
-	normNum := base / peak
+	return peak
+}
+
+// amplify multiplies every sample in Wav by the linear gain.
+func (w *Wav) amplify(gain float64) {
 	for i := 0; i < int(w.NumSamples); i++ {
 		x := w.data[i]
-		x *= normNum
+		x *= gain
 		w.data[i] = x
 	}
 }
 
+// Normalize normalizes a track according to the desired peak in dBFS.
+func (w *Wav) Normalize(desiredPeak float64) {
+	base := w.fromDBFS(desiredPeak)
+	w.amplify(base / w.peak())
+}
+
 // Compress is a dynamic range compressor.
 func (w *Wav) Compress(threshold, ratio, tatt, trel, tla, knee, gain float64, makeup bool) {
-	threshold = math.Pow(2, float64(w.bitsPerSample-1)) * math.Pow(10, threshold/20)
+	threshold = w.fromDBFS(threshold)
 	sr := float64(w.sampleRate)
 	tatt *= math.Pow(10, -3) // attack time
 	trel *= math.Pow(10, -3) // release time
 	tla *= math.Pow(10, -3)  // lookahead
-	knee = math.Pow(2, float64(w.bitsPerSample-1)) * math.Pow(10, (knee/20))
+	knee = w.fromDBFS(knee)
 	var att, rel float64
 	if tatt == 0 {
 		att = 0.0
@@ -269,6 +466,59 @@ func (w *Wav) Compress(threshold, ratio, tatt, trel, tla, knee, gain float64, ma
 	}
 }
 
+// Limit is a lookahead brickwall limiter keeping every sample below the ceiling in dBFS.
+// Channels are limited together so the stereo image is kept.
+func (w *Wav) Limit(ceiling, tla, trel float64) {
+	ceiling = w.fromDBFS(ceiling)
+	sr := float64(w.sampleRate)
+	nla := int(math.Max(1, math.Round(sr*tla*math.Pow(10, -3)))) // lookahead
+	rel := 0.0
+	if trel > 0 {
+		rel = math.Exp(-1.0 / (sr * trel * math.Pow(10, -3)))
+	}
+	nch := int(math.Max(1, float64(w.numChannels)))
+	frames := len(w.data) / nch
+
+	// Gain each frame needs to stay below the ceiling.
+	need := make([]float64, frames)
+	for i := range need {
+		need[i] = 1.0
+		for c := 0; c < nch; c++ {
+			if x := math.Abs(w.data[i*nch+c]); x*need[i] > ceiling {
+				need[i] = ceiling / x
+			}
+		}
+	}
+
+	// Smallest gain needed within the lookahead, recovering at the release rate.
+	hold := make([]float64, frames)
+	env := 1.0
+	for i := range hold {
+		g := 1.0
+		for j := i; j < i+nla && j < frames; j++ {
+			g = math.Min(g, need[j])
+		}
+		env = math.Min(g, 1-(1-env)*rel)
+		hold[i] = env
+	}
+
+	// Averaging over the lookahead ramps the gain down before each peak without overshooting it.
+	var sum float64
+	for i := 0; i < frames; i++ {
+		sum += hold[i]
+		if i >= nla {
+			sum -= hold[i-nla]
+		}
+		g := sum / float64(nla)
+		if i < nla {
+			g = (sum + float64(nla-1-i)*hold[0]) / float64(nla)
+		}
+		for c := 0; c < nch; c++ {
+			w.data[i*nch+c] *= g
+		}
+	}
+}
+
 // RollingAvgLowpass is a low pass filter using rolling average.
 func (w *Wav) RollingAvgLowpass(bandwidth int) {
 	var period []float64
@@ -283,52 +533,75 @@ func (w *Wav) RollingAvgLowpass(bandwidth int) {
 	}
 }
 
-// Biquad is an implementation of the Biquad filter
-func (w *Wav) Biquad(fc, lh int) {
+// biquad is a single second-order section in direct form I.
+// a1, a2, a3 are the feedforward and b1, b2 the feedback coefficients.
+type biquad struct {
+	a1, a2, a3, b1, b2 float64
+	x1, x2, y1, y2     float64
+}
+
+// process filters a single sample through the section.
+func (f *biquad) process(x0 float64) float64 {
+	y := f.a1*x0 + f.a2*f.x1 + f.a3*f.x2 - f.b1*f.y1 - f.b2*f.y2
+	f.x2, f.x1 = f.x1, x0
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// cascade runs x through a fresh copy of the sections in series.
+func cascade(x []float64, sections ...biquad) []float64 {
+	fs := append([]biquad(nil), sections...)
+	y := make([]float64, len(x))
+	for i, smp := range x {
+		for j := range fs {
+			smp = fs[j].process(smp)
+		}
+		y[i] = smp
+	}
+	return y
+}
+
+// filter runs the sections in series over each channel of Wav.
+func (w *Wav) filter(sections ...biquad) {
+	chans := w.Channels()
+	for c := range chans {
+		chans[c] = cascade(chans[c], sections...)
+	}
+	w.SetChannels(chans)
+}
+
+// biquadSection returns the Butterworth section used by Biquad.
+func biquadSection(fc, lh int, sr float64) biquad {
 	r := math.Sqrt(2) // Rez
-	sr := float64(w.sampleRate)
-	var c, a1, a2, a3, b1, b2 float64
+	var c float64
+	var f biquad
 	if lh == 0 { // Low pass
 		c = 1.0 / math.Tan(math.Pi*float64(fc)/sr)
-		a1 = 1.0 / (1.0 + r*c + c*c)
-		a2 = 2 * a1
-		a3 = a1
-		b1 = 2.0 * (1.0 - c*c) * a1
-		b2 = (1.0 - r*c + c*c) * a1
+		f.a1 = 1.0 / (1.0 + r*c + c*c)
+		f.a2 = 2 * f.a1
+		f.a3 = f.a1
+		f.b1 = 2.0 * (1.0 - c*c) * f.a1
+		f.b2 = (1.0 - r*c + c*c) * f.a1
 	} else { // High pass
 		c = math.Tan(math.Pi * float64(fc) / sr)
-		a1 = 1.0 / (1.0 + r*c + c*c)
-		a2 = -2 * a1
-		a3 = a1
-		b1 = 2.0 * (c*c - 1.0) * a1
-		b2 = (1.0 - r*c + c*c) * a1
-	}
-	var period []float64
-	for i := 0; i < int(w.NumSamples); i++ {
-		y := 0.0
-		x0 := w.data[i]
-		if len(period) == 2 {
-			x1 := period[1]
-			x2 := period[0]
-			y1 := w.data[i-1]
-			y2 := w.data[i-2]
-			y = a1*x0 + a2*x1 + a3*x2 - b1*y1 - b2*y2
-			period = period[1:]
-		} else {
-			y = x0
-		}
-		period = append(period, x0)
-		w.data[i] = y
+		f.a1 = 1.0 / (1.0 + r*c + c*c)
+		f.a2 = -2 * f.a1
+		f.a3 = f.a1
+		f.b1 = 2.0 * (c*c - 1.0) * f.a1
+		f.b2 = (1.0 - r*c + c*c) * f.a1
 	}
+	return f
 }
 
-// WindowedSinc is a Hamming windowed-sinc  low pass filter
-func (w *Wav) WindowedSinc(cutoff, bandwidth int) {
-	if cutoff > int(w.sampleRate)/2 {
-		panic("Cutoff frequency too high.")
-	}
-	FC := float64(cutoff) / float64(w.sampleRate) // Cut off (freq/sample rate)
-	M := bandwidth                                // Filter roll off
+// Biquad is an implementation of the Biquad filter
+func (w *Wav) Biquad(fc, lh int) {
+	w.filter(biquadSection(fc, lh, float64(w.sampleRate)))
+}
+
+// sincKernel returns the Hamming windowed-sinc kernel used by WindowedSinc.
+func sincKernel(cutoff, bandwidth int, sr float64) []float64 {
+	FC := float64(cutoff) / sr // Cut off (freq/sample rate)
+	M := bandwidth             // Filter roll off
 	kernel := make([]float64, M)
 	for i := range kernel {
 		if i-M/2 == 0 {
@@ -345,6 +618,16 @@ func (w *Wav) WindowedSinc(cutoff, bandwidth int) {
 	for i := range kernel {
 		kernel[i] /= sum
 	}
+	return kernel
+}
+
+// WindowedSinc is a Hamming windowed-sinc  low pass filter
+func (w *Wav) WindowedSinc(cutoff, bandwidth int) {
+	if cutoff > int(w.sampleRate)/2 {
+		panic("Cutoff frequency too high.")
+	}
+	M := bandwidth
+	kernel := sincKernel(cutoff, bandwidth, float64(w.sampleRate))
 	var filteredData []float64
 	for j := M; j < int(w.NumSamples); j++ {
 		y := 0.0
@@ -356,6 +639,7 @@ func (w *Wav) WindowedSinc(cutoff, bandwidth int) {
 		filteredData = append(filteredData, y)
 	}
 	w.data = filteredData
+	w.updateHeader()
 }
 
 // Highpass is a basic highpass filter
@@ -373,20 +657,20 @@ func (w *Wav) Highpass() {
 	}
 }
 
-// Chebyshev sub routine
+// Chebyshev sub routine, returns the coefficients of the section for pole pair P of NP.
 func _cheb(FC, PR, LH, NP, P float64) (float64, float64, float64, float64, float64) {
 	RP := -math.Cos(math.Pi/(NP*2) + (P-1)*math.Pi/NP)
 	IP := math.Sin(math.Pi/(NP*2) + (P-1)*math.Pi/NP)
-	if PR != 0 {
+	if PR != 0 { // Warp from a circle to an ellipse
 		ES := math.Sqrt(math.Pow((100/(100-PR)), 2) - 1)
-		VX := (1/NP)*math.Log(1/ES) + math.Sqrt(math.Pow(1/ES, 2)+1)
-		KX := (1/NP)*math.Log(1/ES) + math.Sqrt(math.Pow(1/ES, 2)-1)
+		VX := (1 / NP) * math.Log(1/ES+math.Sqrt(math.Pow(1/ES, 2)+1))
+		KX := (1 / NP) * math.Log(1/ES+math.Sqrt(math.Pow(1/ES, 2)-1))
 		KX = (math.Exp(KX) + math.Exp(-KX)) / 2
 		RP = RP * ((math.Exp(VX) - math.Exp(-VX)) / 2) / KX
 		IP = IP * ((math.Exp(VX) + math.Exp(-VX)) / 2) / KX
 	}
-	// fmt.Printf("RP=%f, IP=%f\n", RP, IP)
 
+	// s-domain to z-domain conversion
 	T := 2 * math.Tan(0.5)
 	W := 2 * math.Pi * FC
 	M := math.Pow(RP, 2) + math.Pow(IP, 2)
@@ -396,8 +680,8 @@ func _cheb(FC, PR, LH, NP, P float64) (float64, float64, float64, float64, float
 	X2 := math.Pow(T, 2) / D
 	Y1 := (8 - 2*M*math.Pow(T, 2)) / D
 	Y2 := (-4 - 4*RP*T - M*math.Pow(T, 2)) / D
-	// fmt.Printf("T=%f, W=%f, M=%f, D=%f\n, X0=%f, X1=%f, X2=%f, Y1=%f, Y2=%f\n", T, W, M, D, X0, X1, X2, Y1, Y2)
 
+	// LP to LP, or LP to HP transform
 	K := 0.0
 	if LH == 1 {
 		K = -math.Cos(W/2+0.5) / math.Cos(W/2-0.5)
@@ -416,64 +700,53 @@ func _cheb(FC, PR, LH, NP, P float64) (float64, float64, float64, float64, float
 		A1 = -A1
 		B1 = -B1
 	}
-	// fmt.Printf("A0=%f, A1=%f, A2=%f, B1=%f, B2=%f\n, K=%f, D=%f\n", A0, A1, A2, B1, B2, K, D)
 	return A0, A1, A2, B1, B2
 }
 
-// Chebyshev is an implementation of the Chebyshev filter
-// WIP
-func (w *Wav) Chebyshev() {
-	var A [22]float64
-	var B [22]float64
-	var TA [22]float64
-	var TB [22]float64
-
-	A[2] = 1.0
-	B[2] = 1.0
-
-	FC := 0.1 // Cut off
-	LH := 0.0 // 0: LP, 1: HP
-	PR := 0.0 // Percent ripple
-	NP := 4.0 // Number of poles
-
-	for P := 2; float64(P) < NP/2; P++ {
+// chebyshevSections returns the Chebyshev filter as one biquad per pole pair.
+// FC is the cutoff as a fraction of the sample rate, LH 0 for low pass and 1 for high pass,
+// PR the percent ripple in the passband and NP the even number of poles.
+// The first section is scaled for unity gain at DC for low pass and at Nyquist for high pass.
+func chebyshevSections(FC, LH, PR, NP float64) []biquad {
+	var sections []biquad
+	GAIN := 1.0
+	for P := 1; float64(P) <= NP/2; P++ {
 		A0, A1, A2, B1, B2 := _cheb(FC, PR, LH, NP, float64(P))
-		for I := 0; I < 22; I++ {
-			TA[I] = A[I]
-			TB[I] = B[I]
-		}
-		for I := 2; I < 22; I++ {
-			A[I] = A0*TA[I] + A1*TA[I-1] + A2*TA[I-2]
-			B[I] = TB[I] - B1*TB[I-1] - B2*TB[I-2]
-		}
-	}
-
-	B[2] = 0
-	for I := 0; I < 20; I++ {
-		A[I] = A[I+2]
-		B[I] = -B[I+2]
-	}
-
-	SA := 0.0
-	SB := 0.0
-	for I := 0; I < 20; I++ {
+		// The recursion adds B1, B2 times the previous outputs, the biquad subtracts them.
+		sections = append(sections, biquad{a1: A0, a2: A1, a3: A2, b1: -B1, b2: -B2})
 		if LH == 1 {
-			SA = SA + A[I]*math.Pow(-1, float64(I))
-			SB = SB + B[I]*math.Pow(-1, float64(I))
-			fmt.Printf("%f, SB=%f\n", SA, SB)
+			GAIN *= (A0 - A1 + A2) / (1 + B1 - B2)
 		} else {
-			SA = SA + A[I]
-			SB = SB + B[I]
+			GAIN *= (A0 + A1 + A2) / (1 - B1 - B2)
 		}
 	}
+	if len(sections) > 0 {
+		sections[0].a1 /= GAIN
+		sections[0].a2 /= GAIN
+		sections[0].a3 /= GAIN
+	}
+	return sections
+}
 
-	GAIN := SA / (1 - SB)
-	for I := 0; I < 20; I++ {
-		A[I] = A[I] / GAIN
+// checkChebyshev panics on a cutoff, as a fraction of the sample rate, ripple or number of poles
+// Chebyshev cannot design.
+func checkChebyshev(FC, ripple float64, poles int) {
+	if FC <= 0 || FC >= 0.5 {
+		panic("Cutoff frequency must be between 0 and half the sample rate.")
 	}
-	for i := 0; i < int(w.NumSamples); i++ {
-		x := w.data[i]
-		x *= GAIN
-		w.data[i] = x
+	if poles < 2 || poles > 20 || poles%2 != 0 {
+		panic("Number of poles must be even, from 2 to 20.")
+	}
+	if ripple < 0 || ripple > 29 {
+		panic("Percent ripple must be from 0 to 29.")
 	}
 }
+
+// Chebyshev is an implementation of the Type I Chebyshev filter.
+// lh 0 is low pass and 1 high pass, ripple is the percent ripple in the passband
+// and poles the even number of poles, from 2 to 20.
+func (w *Wav) Chebyshev(fc float64, lh int, ripple float64, poles int) {
+	FC := fc / float64(w.sampleRate)
+	checkChebyshev(FC, ripple, poles)
+	w.filter(chebyshevSections(FC, float64(lh), ripple, float64(poles))...)
+}
@@ -52,3 +52,56 @@ func BenchmarkDFTReconstruction(b *testing.B) {
 		track.ReconSignal(idft)
 	}
 }
+
+func newTestWav(sampleRate int, chans ...[]float64) *Wav {
+	track := NewWav()
+	track.audioFormat = 1
+	track.sampleRate = uint32(sampleRate)
+	track.bitsPerSample = 16
+	track.subchunk1Size = 16
+	track.SetChannels(chans)
+	return track
+}
+
+func TestChannelsRoundTrip(t *testing.T) {
+	l := []float64{1, 2, 3}
+	r := []float64{-1, -2, -3}
+	track := newTestWav(48000, l, r)
+
+	if got, want := track.data, []float64{1, -1, 2, -2, 3, -3}; !floatSliceEqual(got, want) {
+		t.Errorf("got %f, wanted %f", got, want)
+	}
+	chans := track.Channels()
+	if !floatSliceEqual(chans[0], l) || !floatSliceEqual(chans[1], r) {
+		t.Errorf("got %f, wanted %f and %f", chans, l, r)
+	}
+}
+
+func TestMix24(t *testing.T) {
+	a := newTestWav(48000, []float64{1 << 22, -(1 << 22), 1 << 22})
+	b := newTestWav(48000, []float64{1 << 21, -(1 << 23), 1 << 22})
+	for _, track := range []*Wav{a, b} {
+		track.bitsPerSample = 24
+		track.updateHeader()
+	}
+	mix := NewWav()
+	mix.Mix(a, b)
+	if got, want := mix.data, []float64{3 << 21, -(1 << 23), 1<<23 - 1}; !floatSliceEqual(got, want) {
+		t.Errorf("got %f, wanted %f", got, want)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	x := make([]float64, 4800)
+	for i := range x {
+		x[i] = 8000 * math.Sin(float64(i)/10)
+	}
+	x[100], x[2000], x[2003] = 32000, -32000, 30000
+	track := newTestWav(48000, x, x)
+	track.Limit(-6, 5, 50)
+
+	ceiling := 32768 * math.Pow(10, -6.0/20)
+	if got := track.peak(); got > ceiling+tolerance {
+		t.Errorf("got peak %f, wanted at most %f", got, ceiling)
+	}
+}
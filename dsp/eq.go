@@ -0,0 +1,18 @@
+package dsp
+
+import (
+	"math"
+)
+
+// Preset is a parametric EQ, any number of cookbook bands after a preamp gain in dB.
+type Preset struct {
+	Preamp float64 `json:"preamp,omitempty" yaml:"preamp,omitempty"`
+	Bands  []Band  `json:"bands" yaml:"bands"`
+}
+
+// EQ runs each channel of Wav through the preamp and every band of the preset in a single pass,
+// each channel keeping its own filter state.
+func (w *Wav) EQ(p Preset) {
+	w.amplify(math.Pow(10, p.Preamp/20))
+	w.Cookbook(p.Bands...)
+}
@@ -0,0 +1,22 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEQ(t *testing.T) {
+	track := newTestWav(48000, sine(48000, 100, -12, 1), sine(48000, 12000, -12, 1))
+	track.EQ(Preset{Preamp: -3, Bands: []Band{
+		{Type: LowShelf, Frequency: 300, Gain: 6},
+		{Type: HighShelf, Frequency: 2000, Gain: -6},
+		{Type: PeakingEQ, Frequency: 1000, Gain: 3, Q: 4},
+	}})
+	chans := track.Channels()
+	if got := track.toDBFS(rms(chans[0][4800:])) + 3.0103; math.Abs(got - -9) > 0.1 {
+		t.Errorf("got %f dBFS through the low shelf, wanted -9", got)
+	}
+	if got := track.toDBFS(rms(chans[1][4800:])) + 3.0103; math.Abs(got - -21) > 0.1 {
+		t.Errorf("got %f dBFS through the high shelf, wanted -21", got)
+	}
+}
@@ -0,0 +1,75 @@
+package dsp
+
+import (
+	"math"
+)
+
+// Info holds the header, derived fields and basic statistics of a Wav.
+type Info struct {
+	ChunkID       string         `json:"chunkID"`
+	ChunkSize     uint32         `json:"chunkSize"`
+	Format        string         `json:"format"`
+	Subchunk1ID   string         `json:"subchunk1ID"`
+	Subchunk1Size uint32         `json:"subchunk1Size"`
+	AudioFormat   uint16         `json:"audioFormat"`
+	NumChannels   uint16         `json:"numChannels"`
+	SampleRate    uint32         `json:"sampleRate"`
+	ByteRate      uint32         `json:"byteRate"`
+	BlockAlign    uint16         `json:"blockAlign"`
+	BitsPerSample uint16         `json:"bitsPerSample"`
+	Subchunk2ID   string         `json:"subchunk2ID"`
+	Subchunk2Size uint32         `json:"subchunk2Size"`
+	NumSamples    uint32         `json:"numSamples"`
+	SampleSize    uint16         `json:"sampleSize"`
+	Duration      float64        `json:"duration"`
+	Channels      []ChannelLevel `json:"channels"`
+}
+
+// ChannelLevel holds the sample peak, true peak and RMS level of a single channel.
+type ChannelLevel struct {
+	Peak         float64 `json:"peak"`
+	PeakDBFS     float64 `json:"peakDBFS"`
+	TruePeak     float64 `json:"truePeak"`
+	TruePeakDBTP float64 `json:"truePeakDBTP"`
+	RMS          float64 `json:"rms"`
+	RMSDBFS      float64 `json:"rmsDBFS"`
+}
+
+// Info returns the header, derived fields and per channel levels of Wav.
+func (w *Wav) Info() Info {
+	info := Info{
+		ChunkID:       string(w.chunkID[:]),
+		ChunkSize:     w.chunkSize,
+		Format:        string(w.format[:]),
+		Subchunk1ID:   string(w.subchunk1ID[:]),
+		Subchunk1Size: w.subchunk1Size,
+		AudioFormat:   w.audioFormat,
+		NumChannels:   w.numChannels,
+		SampleRate:    w.sampleRate,
+		ByteRate:      w.byteRate,
+		BlockAlign:    w.blockAlign,
+		BitsPerSample: w.bitsPerSample,
+		Subchunk2ID:   string(w.subchunk2ID[:]),
+		Subchunk2Size: w.subchunk2Size,
+		NumSamples:    w.NumSamples,
+		SampleSize:    w.SampleSize,
+		Duration:      w.Duration,
+	}
+	for _, ch := range w.Channels() {
+		var lv ChannelLevel
+		for _, x := range ch {
+			if math.Abs(x) > lv.Peak {
+				lv.Peak = math.Abs(x)
+			}
+		}
+		if len(ch) > 0 {
+			lv.RMS = rms(ch)
+		}
+		lv.TruePeak = truePeak(ch)
+		lv.PeakDBFS = w.toDBFS(lv.Peak)
+		lv.TruePeakDBTP = w.toDBFS(lv.TruePeak)
+		lv.RMSDBFS = w.toDBFS(lv.RMS)
+		info.Channels = append(info.Channels, lv)
+	}
+	return info
+}
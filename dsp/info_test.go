@@ -0,0 +1,21 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInfoLevels(t *testing.T) {
+	track := newTestWav(8000, []float64{16384, -16384}, []float64{0, 0})
+	info := track.Info()
+
+	if info.NumChannels != 2 || info.NumSamples != 4 || info.Duration != 2.0/8000 {
+		t.Errorf("got %d channels, %d samples, %fs", info.NumChannels, info.NumSamples, info.Duration)
+	}
+	if got, want := info.Channels[0].PeakDBFS, -6.0206; math.Abs(got-want) > 1e-4 {
+		t.Errorf("got peak %f dBFS, wanted %f", got, want)
+	}
+	if got := info.Channels[1].RMSDBFS; got != MinDBFS {
+		t.Errorf("got silent RMS %f dBFS, wanted %f", got, MinDBFS)
+	}
+}
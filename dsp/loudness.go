@@ -0,0 +1,191 @@
+package dsp
+
+import (
+	"math"
+	"sort"
+)
+
+// Loudness holds an ITU-R BS.1770-4 / EBU R128 loudness measurement.
+// Loudness values are in LUFS and the loudness range is in LU.
+type Loudness struct {
+	Integrated   float64   `json:"integrated"`
+	MomentaryMax float64   `json:"momentaryMax"`
+	ShortTermMax float64   `json:"shortTermMax"`
+	Range        float64   `json:"range"`
+	Momentary    []float64 `json:"-"` // 400ms windows every 100ms
+	ShortTerm    []float64 `json:"-"` // 3s windows every 100ms
+}
+
+// kWeighting returns the two K-weighting stages of BS.1770 for the given sample rate,
+// a high shelf modelling the head followed by the RLB highpass.
+func kWeighting(sr float64) []biquad {
+	f0 := 1681.974450955533
+	g := 3.999843853973347
+	q := 0.7071752369554196
+	k := math.Tan(math.Pi * f0 / sr)
+	vh := math.Pow(10, g/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf := biquad{
+		a1: (vh + vb*k/q + k*k) / a0,
+		a2: 2 * (k*k - vh) / a0,
+		a3: (vh - vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - 1) / a0,
+		b2: (1 - k/q + k*k) / a0,
+	}
+
+	f0 = 38.13547087602444
+	q = 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / sr)
+	a0 = 1 + k/q + k*k
+	highpass := biquad{
+		a1: 1,
+		a2: -2,
+		a3: 1,
+		b1: 2 * (k*k - 1) / a0,
+		b2: (1 - k/q + k*k) / a0,
+	}
+	return []biquad{shelf, highpass}
+}
+
+// channelWeights returns the BS.1770 weighting of each channel, excluding the LFE of 5.1 and 7.1.
+func channelWeights(channels int) []float64 {
+	g := make([]float64, channels)
+	for i := range g {
+		g[i] = 1.0
+	}
+	if channels == 6 || channels == 8 {
+		g[LFE] = 0
+		for i := BL; i < channels; i++ {
+			g[i] = 1.41
+		}
+	}
+	return g
+}
+
+// lufs converts a weighted mean square into LUFS.
+func lufs(power float64) float64 {
+	return math.Max(-0.691+10*math.Log10(power), MinDBFS)
+}
+
+// gatedMean returns the mean of the block powers whose loudness is above the gate.
+func gatedMean(powers []float64, gate float64) (float64, int) {
+	var sum float64
+	var n int
+	for _, p := range powers {
+		if lufs(p) > gate {
+			sum += p
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return sum / float64(n), n
+}
+
+// blockPowers returns the mean power of each window of the given number of steps.
+func blockPowers(steps []float64, window int) []float64 {
+	var powers []float64
+	for i := 0; i+window <= len(steps); i++ {
+		powers = append(powers, avg(steps[i:i+window]))
+	}
+	return powers
+}
+
+// Loudness measures the loudness of Wav according to ITU-R BS.1770-4 and EBU Tech 3342.
+func (w *Wav) Loudness() Loudness {
+	const absoluteGate = -70.0
+	sr := float64(w.sampleRate)
+	step := int(math.Round(sr / 10))
+	fullScale := math.Pow(2, float64(w.bitsPerSample-1))
+
+	// Weighted mean square of every 100ms step, the common divisor of all windows.
+	chans := w.Channels()
+	weights := channelWeights(len(chans))
+	var steps []float64
+	for c, ch := range chans {
+		if weights[c] == 0 {
+			continue
+		}
+		x := make([]float64, len(ch))
+		for i := range ch {
+			x[i] = ch[i] / fullScale
+		}
+		y := cascade(x, kWeighting(sr)...)
+		for s := 0; (s+1)*step <= len(y); s++ {
+			if s == len(steps) {
+				steps = append(steps, 0)
+			}
+			var sum float64
+			for _, v := range y[s*step : (s+1)*step] {
+				sum += v * v
+			}
+			steps[s] += weights[c] * sum / float64(step)
+		}
+	}
+
+	var l Loudness
+	momentary := blockPowers(steps, 4)
+	shortTerm := blockPowers(steps, 30)
+	l.MomentaryMax, l.ShortTermMax = MinDBFS, MinDBFS
+	for _, p := range momentary {
+		l.Momentary = append(l.Momentary, lufs(p))
+		l.MomentaryMax = math.Max(l.MomentaryMax, lufs(p))
+	}
+	for _, p := range shortTerm {
+		l.ShortTerm = append(l.ShortTerm, lufs(p))
+		l.ShortTermMax = math.Max(l.ShortTermMax, lufs(p))
+	}
+
+	// Integrated loudness of the momentary blocks, gated absolutely then 10 LU below the ungated result.
+	l.Integrated = MinDBFS
+	if mean, n := gatedMean(momentary, absoluteGate); n > 0 {
+		if mean, n = gatedMean(momentary, math.Max(lufs(mean)-10, absoluteGate)); n > 0 {
+			l.Integrated = lufs(mean)
+		}
+	}
+
+	// Loudness range between the 10th and 95th percentile of short-term blocks gated 20 LU below.
+	if mean, n := gatedMean(shortTerm, absoluteGate); n > 0 {
+		gate := math.Max(lufs(mean)-20, absoluteGate)
+		var gated []float64
+		for _, v := range l.ShortTerm {
+			if v > gate {
+				gated = append(gated, v)
+			}
+		}
+		if len(gated) > 0 {
+			sort.Float64s(gated)
+			lo := gated[int(math.Round(float64(len(gated)-1)*0.10))]
+			hi := gated[int(math.Round(float64(len(gated)-1)*0.95))]
+			l.Range = hi - lo
+		}
+	}
+	return l
+}
+
+// NormalizeLoudness applies the gain needed to bring Wav to the target integrated loudness in LUFS.
+// If the gain would push the true peak above the ceiling in dBTP, the peaks are brought down with Limit when limit is set,
+// otherwise the gain is reduced to meet the ceiling. It returns the applied gain in dB and whether the ceiling was in conflict with the target.
+func (w *Wav) NormalizeLoudness(target, ceiling float64, limit bool) (float64, bool) {
+	integrated := w.Loudness().Integrated
+	if integrated == MinDBFS {
+		return 0, false
+	}
+	gain := target - integrated
+	headroom := ceiling - w.toDBFS(w.TruePeak())
+	conflict := gain > headroom
+	if conflict && !limit {
+		gain = headroom
+	}
+	w.amplify(math.Pow(10, gain/20))
+	if conflict && limit {
+		// The limiter works on sample peaks, trim whatever intersample overs it leaves.
+		w.Limit(ceiling, 5, 50)
+		if over := w.toDBFS(w.TruePeak()) - ceiling; over > 0 {
+			w.amplify(math.Pow(10, -over/20))
+		}
+	}
+	return gain, conflict
+}
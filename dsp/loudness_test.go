@@ -0,0 +1,58 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func sine(sampleRate int, freq, dbfs, seconds float64) []float64 {
+	amp := 32768 * math.Pow(10, dbfs/20)
+	x := make([]float64, int(float64(sampleRate)*seconds))
+	for i := range x {
+		x[i] = amp * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))
+	}
+	return x
+}
+
+// EBU Tech 3341 case 1: a stereo 1 kHz sine at -23 dBFS reads -23 LUFS.
+func TestLoudnessSine(t *testing.T) {
+	for _, sr := range []int{44100, 48000} {
+		x := sine(sr, 1000, -23, 10)
+		l := newTestWav(sr, x, x).Loudness()
+		for _, got := range []float64{l.Integrated, l.MomentaryMax, l.ShortTermMax} {
+			if math.Abs(got - -23) > 0.1 {
+				t.Errorf("%d Hz: got %f LUFS, wanted -23", sr, got)
+			}
+		}
+		if l.Range > 0.1 {
+			t.Errorf("%d Hz: got range %f LU, wanted 0", sr, l.Range)
+		}
+	}
+}
+
+// EBU Tech 3342 case 1: 20 s at -20 LUFS followed by 20 s at -30 LUFS has a 10 LU range.
+func TestLoudnessRange(t *testing.T) {
+	x := append(sine(48000, 1000, -20, 20), sine(48000, 1000, -30, 20)...)
+	l := newTestWav(48000, x, x).Loudness()
+	if math.Abs(l.Range-10) > 0.1 {
+		t.Errorf("got range %f LU, wanted 10", l.Range)
+	}
+}
+
+func TestNormalizeLoudness(t *testing.T) {
+	x := sine(48000, 1000, -30, 5)
+	track := newTestWav(48000, x, x)
+	if _, conflict := track.NormalizeLoudness(-16, -1, false); conflict {
+		t.Errorf("got conflict normalizing to -16 LUFS")
+	}
+	if got := track.Loudness().Integrated; math.Abs(got - -16) > 0.1 {
+		t.Errorf("got %f LUFS, wanted -16", got)
+	}
+
+	if _, conflict := track.NormalizeLoudness(0, -1, true); !conflict {
+		t.Errorf("got no conflict normalizing to 0 LUFS")
+	}
+	if got := track.toDBFS(track.peak()); got > -1+1e-6 {
+		t.Errorf("got peak %f dBFS, wanted at most -1", got)
+	}
+}
@@ -0,0 +1,136 @@
+package dsp
+
+import (
+	"math"
+)
+
+// onsetFloor is the level in dB below which spectral changes are ignored by the onset detector.
+const onsetFloor = -80.0
+
+// Tempo is a tempo estimate and the beat grid it implies.
+type Tempo struct {
+	BPM        float64 `json:"bpm"`
+	Confidence float64 `json:"confidence"`
+	Offset     float64 `json:"offset"` // Time of the first beat in seconds
+}
+
+// OnsetStrength returns the spectral flux of Wav, the summed rise in dB of every STFT bin
+// between consecutive frames, normalized to zero mean and unit variance.
+// It also returns the time of the first frame and the number of frames per second.
+func (w *Wav) OnsetStrength(size, hop int) ([]float64, float64, float64) {
+	s := w.STFT(size, hop, "hann")
+	odf := make([]float64, len(s.Magnitudes))
+	for t := 1; t < len(s.Magnitudes); t++ {
+		for k, m := range s.Magnitudes[t] {
+			rise := math.Max(m, onsetFloor) - math.Max(s.Magnitudes[t-1][k], onsetFloor)
+			if rise > 0 {
+				odf[t] += rise
+			}
+		}
+	}
+
+	mean := avg(odf)
+	var variance float64
+	for _, v := range odf {
+		variance += (v - mean) * (v - mean) / float64(len(odf))
+	}
+	for t := range odf {
+		odf[t] -= mean
+		if variance > 0 {
+			odf[t] /= math.Sqrt(variance)
+		}
+	}
+	return odf, s.Times[0], float64(w.sampleRate) / float64(hop)
+}
+
+// Onsets returns the times in seconds of the onsets in Wav. An onset is a peak of the onset strength
+// that is the largest within 50ms, at least delta above the local mean and at least minGap seconds after the previous one.
+func (w *Wav) Onsets(size, hop int, delta, minGap float64) []float64 {
+	odf, t0, fps := w.OnsetStrength(size, hop)
+	near := int(math.Max(1, math.Round(0.05*fps)))
+	before := 4 * near
+	last := math.Inf(-1)
+	var onsets []float64
+	for t := range odf {
+		lo := int(math.Max(0, float64(t-before)))
+		hi := int(math.Min(float64(len(odf)), float64(t+near+1)))
+		isMax := true
+		for j := int(math.Max(0, float64(t-near))); j < hi; j++ {
+			if odf[j] > odf[t] {
+				isMax = false
+				break
+			}
+		}
+		if !isMax || odf[t] < avg(odf[lo:hi])+delta {
+			continue
+		}
+		if time := t0 + float64(t)/fps; time-last >= minGap {
+			onsets = append(onsets, time)
+			last = time
+		}
+	}
+	return onsets
+}
+
+// autocorrelation returns the autocorrelation of x for every lag up to maxLag.
+func autocorrelation(x []float64, maxLag int) []float64 {
+	r := make([]float64, maxLag+1)
+	for l := range r {
+		for i := 0; i+l < len(x); i++ {
+			r[l] += x[i] * x[i+l]
+		}
+	}
+	return r
+}
+
+// interpolate returns x at a fractional index, interpolating linearly.
+func interpolate(x []float64, i float64) float64 {
+	n := int(i)
+	if n+1 >= len(x) {
+		return x[len(x)-1]
+	}
+	return x[n] + (i-float64(n))*(x[n+1]-x[n])
+}
+
+// Tempo estimates the tempo of Wav between minBPM and maxBPM. Each candidate is scored by a comb
+// over the autocorrelation of the onset strength at 1 to 4 beat periods, weighted towards 120 BPM
+// to settle octave ambiguities. Confidence is the mean normalized autocorrelation of the comb.
+func (w *Wav) Tempo(minBPM, maxBPM float64) Tempo {
+	odf, t0, fps := w.OnsetStrength(2048, 512)
+	acf := autocorrelation(odf, int(math.Min(4*60*fps/minBPM+1, float64(len(odf)-1))))
+	var best Tempo
+	if acf[0] == 0 {
+		return best
+	}
+	bestScore := math.Inf(-1)
+	for i := 0; minBPM+float64(i)/10 <= maxBPM; i++ {
+		bpm := minBPM + float64(i)/10
+		period := 60 * fps / bpm
+		var comb float64
+		for k := 1.0; k <= 4; k++ {
+			if k*period+1 < float64(len(acf)) {
+				comb += interpolate(acf, k*period) / acf[0] / 4
+			}
+		}
+		score := comb * math.Exp(-0.5*math.Pow(math.Log2(bpm/120), 2))
+		if score > bestScore {
+			bestScore = score
+			best = Tempo{BPM: bpm, Confidence: math.Max(0, math.Min(1, comb))}
+		}
+	}
+
+	// The first beat is the phase whose grid collects the most onset strength.
+	period := 60 * fps / best.BPM
+	bestSum := math.Inf(-1)
+	for phase := 0; phase < int(math.Ceil(period)); phase++ {
+		var sum float64
+		for t := float64(phase); int(t) < len(odf); t += period {
+			sum += odf[int(t)]
+		}
+		if sum > bestSum {
+			bestSum = sum
+			best.Offset = t0 + float64(phase)/fps
+		}
+	}
+	return best
+}
@@ -0,0 +1,46 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+// clicks returns short decaying noise bursts every period seconds.
+func clicks(sampleRate int, period, seconds float64) []float64 {
+	x := make([]float64, int(float64(sampleRate)*seconds))
+	seed := uint32(1)
+	for t := 0.25; t < seconds; t += period {
+		start := int(t * float64(sampleRate))
+		for i := 0; i < sampleRate/50 && start+i < len(x); i++ {
+			seed = seed*1664525 + 1013904223
+			noise := float64(int32(seed)) / math.MaxInt32
+			x[start+i] = 16000 * noise * math.Exp(-float64(i)/float64(sampleRate)*200)
+		}
+	}
+	return x
+}
+
+func TestOnsets(t *testing.T) {
+	onsets := newTestWav(44100, clicks(44100, 0.5, 4)).Onsets(2048, 512, 0.5, 0.1)
+	if len(onsets) != 8 {
+		t.Fatalf("got %d onsets %f, wanted 8", len(onsets), onsets)
+	}
+	for i, got := range onsets {
+		if want := 0.25 + 0.5*float64(i); math.Abs(got-want) > 0.03 {
+			t.Errorf("got onset at %f, wanted %f", got, want)
+		}
+	}
+}
+
+func TestTempo(t *testing.T) {
+	tempo := newTestWav(44100, clicks(44100, 0.5, 10)).Tempo(60, 200)
+	if math.Abs(tempo.BPM-120) > 1 {
+		t.Errorf("got %f BPM, wanted 120", tempo.BPM)
+	}
+	if tempo.Confidence < 0.5 {
+		t.Errorf("got confidence %f, wanted at least 0.5", tempo.Confidence)
+	}
+	if beat := math.Mod(tempo.Offset, 0.5); math.Abs(beat-0.25) > 0.03 {
+		t.Errorf("got first beat at %f, wanted on the grid at 0.25", tempo.Offset)
+	}
+}
@@ -0,0 +1,133 @@
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/mjibson/go-dsp/fft"
+)
+
+// PitchFrame is the fundamental frequency estimate of a single frame.
+// Frequency and MIDI are 0 in unvoiced frames.
+type PitchFrame struct {
+	Time       float64 `json:"time"`
+	Frequency  float64 `json:"frequency"`
+	Confidence float64 `json:"confidence"`
+	Voiced     bool    `json:"voiced"`
+	MIDI       float64 `json:"midi"`
+}
+
+// yinDifference returns the YIN difference function of the frame for lags below half its length,
+// d(tau) = sum (x[j] - x[j+tau])^2, using FFT cross-correlation for the product term.
+func yinDifference(frame []float64) []float64 {
+	n := len(frame) / 2
+	size := 1
+	for size < len(frame)+n {
+		size *= 2
+	}
+	a := make([]float64, size)
+	b := make([]float64, size)
+	copy(a, frame)
+	copy(b, frame[:n])
+	A := fft.FFTReal(a)
+	B := fft.FFTReal(b)
+	for i := range A {
+		A[i] *= cmplx.Conj(B[i])
+	}
+	r := fft.IFFT(A)
+
+	// Energy of the window starting at each lag, updated as it slides.
+	var first, energy float64
+	for _, v := range frame[:n] {
+		first += v * v
+	}
+	energy = first
+	d := make([]float64, n)
+	for tau := 0; tau < n; tau++ {
+		d[tau] = first + energy - 2*real(r[tau])
+		energy += frame[tau+n]*frame[tau+n] - frame[tau]*frame[tau]
+	}
+	return d
+}
+
+// yin estimates the period of the frame in samples, searching lags between minLag and maxLag,
+// both limited to the lags a frame of its length can measure. It returns the period and the cumulative mean normalized difference at it.
+func yin(frame []float64, minLag, maxLag int, threshold float64) (float64, float64) {
+	d := yinDifference(frame)
+	if maxLag >= len(d) {
+		maxLag = len(d) - 1
+	}
+	if minLag > maxLag {
+		minLag = maxLag
+	}
+	// Cumulative mean normalized difference.
+	cmnd := make([]float64, len(d))
+	cmnd[0] = 1
+	var sum float64
+	for tau := 1; tau < len(d); tau++ {
+		sum += d[tau]
+		if sum == 0 {
+			cmnd[tau] = 1
+		} else {
+			cmnd[tau] = d[tau] * float64(tau) / sum
+		}
+	}
+
+	// First dip below the threshold, followed down to its minimum, else the global minimum.
+	best := -1
+	for tau := minLag; tau <= maxLag; tau++ {
+		if cmnd[tau] < threshold {
+			for tau+1 <= maxLag && cmnd[tau+1] < cmnd[tau] {
+				tau++
+			}
+			best = tau
+			break
+		}
+	}
+	if best < 0 {
+		best = minLag
+		for tau := minLag; tau <= maxLag; tau++ {
+			if cmnd[tau] < cmnd[best] {
+				best = tau
+			}
+		}
+	}
+
+	period := float64(best)
+	if best > minLag && best < maxLag {
+		s0, s1, s2 := cmnd[best-1], cmnd[best], cmnd[best+1]
+		if den := s0 - 2*s1 + s2; den != 0 {
+			period += 0.5 * (s0 - s2) / den
+		}
+	}
+	return period, cmnd[best]
+}
+
+// FrequencyToMIDI converts a frequency in Hz into a fractional MIDI note number.
+func FrequencyToMIDI(f float64) float64 {
+	return 69 + 12*math.Log2(f/440)
+}
+
+// Pitch tracks the fundamental frequency of Wav with the YIN algorithm,
+// using frames of the given size every hop samples and searching between fmin and fmax Hz.
+// Frames whose normalized difference stays above the threshold are unvoiced.
+func (w *Wav) Pitch(size, hop int, fmin, fmax, threshold float64) []PitchFrame {
+	sr := float64(w.sampleRate)
+	minLag := int(math.Max(2, math.Floor(sr/fmax)))
+	maxLag := int(math.Ceil(sr / fmin))
+	x := w.mono()
+
+	var frames []PitchFrame
+	for i := 0; i+size <= len(x); i += hop {
+		f := PitchFrame{Time: (float64(i) + float64(size)/2) / sr}
+		period, dip := yin(x[i:i+size], minLag, maxLag, threshold)
+		f.Confidence = math.Max(0, 1-dip)
+		if dip < threshold {
+			f.Voiced = true
+			f.Frequency = sr / period
+			f.MIDI = FrequencyToMIDI(f.Frequency)
+		}
+		frames = append(frames, f)
+	}
+	return frames
+}
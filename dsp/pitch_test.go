@@ -0,0 +1,27 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPitchSine(t *testing.T) {
+	x := append(sine(44100, 220, -6, 0.5), make([]float64, 22050)...)
+	frames := newTestWav(44100, x).Pitch(2048, 512, 50, 1000, 0.15)
+
+	voiced, unvoiced := frames[5], frames[len(frames)-1]
+	if !voiced.Voiced || math.Abs(voiced.Frequency-220) > 0.5 || math.Abs(voiced.MIDI-57) > 0.01 {
+		t.Errorf("got %+v, wanted 220 Hz voiced", voiced)
+	}
+	if unvoiced.Voiced || unvoiced.Frequency != 0 {
+		t.Errorf("got %+v in silence, wanted unvoiced", unvoiced)
+	}
+}
+
+func TestPitchShortFrames(t *testing.T) {
+	// Frames too short for the highest frequency search what lags they can rather than panicking.
+	frames := newTestWav(44100, sine(44100, 220, -6, 0.1)).Pitch(64, 32, 50, 1000, 0.15)
+	if len(frames) == 0 {
+		t.Error("got no frames")
+	}
+}
@@ -0,0 +1,100 @@
+package dsp
+
+import (
+	"math"
+)
+
+// Segmental SNR is measured over frames louder than segmentFloor dBFS in the reference,
+// with each frame's SNR limited to the range segmentMin to segmentMax dB.
+const (
+	segmentFloor = -60.0
+	segmentMin   = -10.0
+	segmentMax   = 35.0
+)
+
+// spectralFloor is the level in dB added to every bin before taking the log-spectral distance,
+// well below 16-bit quantization, so near silent bins do not dominate it.
+const spectralFloor = -120.0
+
+// Quality holds objective metrics of a processed track against its reference.
+// SNR and segmental SNR are in dB, log-spectral distance in dB and spectral convergence is a ratio,
+// where 0 is a perfect match.
+type Quality struct {
+	SNR                 float64 `json:"snr"`
+	SegmentalSNR        float64 `json:"segmentalSNR"`
+	LogSpectralDistance float64 `json:"logSpectralDistance"`
+	SpectralConvergence float64 `json:"spectralConvergence"`
+}
+
+// snr returns the ratio in dB of signal to noise power, limited to -MinDBFS for a perfect match.
+func snr(signal, noise float64) float64 {
+	if noise == 0 {
+		return -MinDBFS
+	}
+	return math.Max(MinDBFS, math.Min(-MinDBFS, 10*math.Log10(signal/noise)))
+}
+
+// Quality compares test against Wav as the reference, over frames of the given size
+// with a hann window and half overlap for the spectral metrics. Both tracks are compared
+// relative to full scale over the channels and frames they share.
+func (w *Wav) Quality(test *Wav, size int) Quality {
+	ref, out := w.normalized(), test.normalized()
+	nch := int(math.Min(float64(len(ref)), float64(len(out))))
+	frames := int(math.Min(float64(len(ref[0])), float64(len(out[0]))))
+	win := windowFunc("hann")(size)
+	// Scale so a full scale sine reads 0 dB, as in Spectrum.
+	var scale float64
+	for _, v := range win {
+		scale += v / 2
+	}
+	floor := math.Pow(10, segmentFloor/10)
+	binFloor := math.Pow(10, spectralFloor/10)
+
+	var q Quality
+	var signal, noise, segSum, lsdSum, diffSum, refSum float64
+	var segments, spectra int
+	for c := 0; c < nch; c++ {
+		x, y := ref[c][:frames], out[c][:frames]
+		for i := 0; i < frames; i += size {
+			end := int(math.Min(float64(i+size), float64(frames)))
+			var s, n float64
+			for j := i; j < end; j++ {
+				s += x[j] * x[j]
+				n += (x[j] - y[j]) * (x[j] - y[j])
+			}
+			signal += s
+			noise += n
+			if s/float64(end-i) > floor {
+				segSum += math.Max(segmentMin, math.Min(segmentMax, snr(s, n)))
+				segments++
+			}
+		}
+
+		for i := 0; i == 0 || i+size <= frames; i += size / 2 {
+			end := int(math.Min(float64(i+size), float64(frames)))
+			px := powerSpectrum(x[i:end], win, scale)
+			py := powerSpectrum(y[i:end], win, scale)
+			var d float64
+			for k := range px {
+				l := 10*math.Log10(px[k]+binFloor) - 10*math.Log10(py[k]+binFloor)
+				d += l * l
+				diffSum += math.Pow(math.Sqrt(px[k])-math.Sqrt(py[k]), 2)
+				refSum += px[k]
+			}
+			lsdSum += math.Sqrt(d / float64(len(px)))
+			spectra++
+		}
+	}
+
+	q.SNR = snr(signal, noise)
+	if segments > 0 {
+		q.SegmentalSNR = segSum / float64(segments)
+	}
+	if spectra > 0 {
+		q.LogSpectralDistance = lsdSum / float64(spectra)
+	}
+	if refSum > 0 {
+		q.SpectralConvergence = math.Sqrt(diffSum / refSum)
+	}
+	return q
+}
@@ -0,0 +1,40 @@
+package dsp
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuality(t *testing.T) {
+	x := sine(16000, 440, -6, 2)
+	ref := newTestWav(16000, x)
+
+	if q := ref.Quality(ref, 512); q.SNR != -MinDBFS || q.LogSpectralDistance != 0 || q.SpectralConvergence != 0 {
+		t.Errorf("got %+v against itself, wanted a perfect match", q)
+	}
+
+	// Half the amplitude of noise is 6 dB down in every bin and at half the magnitude.
+	rng := rand.New(rand.NewSource(1))
+	noise := make([]float64, len(x))
+	half := make([]float64, len(x))
+	for i := range noise {
+		noise[i] = 3000 * rng.NormFloat64()
+		half[i] = noise[i] / 2
+	}
+	q := newTestWav(16000, noise).Quality(newTestWav(16000, half), 512)
+	if math.Abs(q.SNR-6.02) > 0.01 || math.Abs(q.LogSpectralDistance-6.02) > 0.01 || math.Abs(q.SpectralConvergence-0.5) > 0.001 {
+		t.Errorf("got %+v at half amplitude", q)
+	}
+
+	// Noise 20 dB below the tone.
+	noisy := make([]float64, len(x))
+	sigma := rms(x) / 10
+	for i := range x {
+		noisy[i] = x[i] + sigma*rng.NormFloat64()
+	}
+	q = ref.Quality(newTestWav(16000, noisy), 512)
+	if math.Abs(q.SNR-20) > 0.2 || math.Abs(q.SegmentalSNR-20) > 0.5 {
+		t.Errorf("got SNR %f and segmental SNR %f dB, wanted 20", q.SNR, q.SegmentalSNR)
+	}
+}
@@ -0,0 +1,192 @@
+package dsp
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Channel positions of the standard WAVE 5.1 and 7.1 layouts.
+const (
+	FL  = iota // Front left
+	FR         // Front right
+	FC         // Front center
+	LFE        // Low frequency effects
+	BL         // Back left
+	BR         // Back right
+	SL         // Side left
+	SR         // Side right
+)
+
+// Matrix holds remix coefficients, one row per output channel and one column per input channel.
+type Matrix [][]float64
+
+// MixLevels are the levels in dB used when building a preset matrix.
+// An LFE level of -Inf leaves the LFE channel out of the mix.
+type MixLevels struct {
+	Center   float64
+	Surround float64
+	LFE      float64
+}
+
+// DefaultMixLevels are the -3 dB center and surround levels of ITU-R BS.775 with the LFE omitted.
+var DefaultMixLevels = MixLevels{Center: -3, Surround: -3, LFE: math.Inf(-1)}
+
+// LoRoMixLevels are the Dolby Lo/Ro defaults of ATSC A/52, -4.5 dB center and -6 dB surround with the LFE omitted.
+var LoRoMixLevels = MixLevels{Center: -4.5, Surround: -6, LFE: math.Inf(-1)}
+
+// PresetMixLevels returns the default levels of the named preset matrix.
+func PresetMixLevels(name string) MixLevels {
+	if name == "loro" {
+		return LoRoMixLevels
+	}
+	return DefaultMixLevels
+}
+
+// Matrices lists the preset matrix names accepted by PresetMatrix.
+var Matrices = []string{"itu", "loro", "ltrt", "upmix"}
+
+func dbToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// PresetMatrix returns the named preset matrix for a track with the given number of channels.
+//
+//	itu:   ITU-R BS.775 5.1/7.1 to stereo fold-down.
+//	loro:  Dolby Lo/Ro stereo downmix, the same equations as itu, meant for LoRoMixLevels.
+//	ltrt:  Dolby Lt/Rt matrix encoded downmix, surrounds are phase shifted into the stereo pair
+//	       with the fixed Pro Logic II coefficients, so the surround level is not used.
+//	upmix: Passive stereo to 5.1 upmix, center from the sum and surrounds from the difference.
+//	       The LFE is the full band sum at the LFE level, it is not low passed.
+func PresetMatrix(name string, channels int, lv MixLevels) Matrix {
+	c := dbToGain(lv.Center)
+	s := dbToGain(lv.Surround)
+	l := dbToGain(lv.LFE)
+	if name == "upmix" {
+		if channels != 2 {
+			panic(fmt.Sprintf("upmix requires a stereo track, got %d channels", channels))
+		}
+		return Matrix{
+			FL:  {1, 0},
+			FR:  {0, 1},
+			FC:  {c / 2, c / 2},
+			LFE: {l / 2, l / 2},
+			BL:  {s, -s},
+			BR:  {-s, s},
+		}
+	}
+	if channels != 6 && channels != 8 {
+		panic(fmt.Sprintf("%s requires a 5.1 or 7.1 track, got %d channels", name, channels))
+	}
+	m := Matrix{make([]float64, channels), make([]float64, channels)}
+	m[0][FL], m[1][FR] = 1, 1
+	m[0][FC], m[1][FC] = c, c
+	m[0][LFE], m[1][LFE] = l, l
+	// 7.1 side surrounds fold into the same side as the back surrounds.
+	left, right := []int{BL}, []int{BR}
+	if channels == 8 {
+		left, right = append(left, SL), append(right, SR)
+	}
+	switch name {
+	case "itu", "loro":
+		for i := range left {
+			m[0][left[i]] = s
+			m[1][right[i]] = s
+		}
+	case "ltrt":
+		// Dolby Pro Logic II surround coefficients.
+		a, b := 0.8716, 0.4903
+		for i := range left {
+			m[0][left[i]], m[0][right[i]] = -a, -b
+			m[1][left[i]], m[1][right[i]] = b, a
+		}
+	default:
+		panic(fmt.Sprintf("unknown matrix: %s", name))
+	}
+	return m
+}
+
+// ReadMatrixFile reads a Matrix from a text file with one row of coefficients per output channel.
+// Coefficients are separated by whitespace or commas, blank lines and lines starting with # are ignored.
+func ReadMatrixFile(path string) Matrix {
+	f, err := os.Open(path)
+	check(err)
+	defer f.Close()
+
+	var m Matrix
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		row := make([]float64, len(fields))
+		for i, v := range fields {
+			row[i], err = strconv.ParseFloat(v, 64)
+			check(err)
+		}
+		if len(m) > 0 && len(row) != len(m[0]) {
+			panic(fmt.Sprintf("matrix row %d has %d coefficients, expected %d", len(m)+1, len(row), len(m[0])))
+		}
+		m = append(m, row)
+	}
+	check(scanner.Err())
+	return m
+}
+
+// Inputs returns the number of input channels the matrix expects.
+func (m Matrix) Inputs() int {
+	if len(m) == 0 {
+		return 0
+	}
+	return len(m[0])
+}
+
+// Normalize scales the matrix so that no output channel can exceed full scale.
+func (m Matrix) Normalize() {
+	var max float64
+	for _, row := range m {
+		var sum float64
+		for _, v := range row {
+			sum += math.Abs(v)
+		}
+		if sum > max {
+			max = sum
+		}
+	}
+	if max == 0 {
+		return
+	}
+	for _, row := range m {
+		for i := range row {
+			row[i] /= max
+		}
+	}
+}
+
+// Remix replaces the channels of Wav with the given matrix applied to them.
+func (w *Wav) Remix(m Matrix) {
+	if m.Inputs() != w.NumChannels() {
+		panic(fmt.Sprintf("matrix expects %d channels, track has %d", m.Inputs(), w.NumChannels()))
+	}
+	in := w.Channels()
+	n := len(in[0])
+	out := make([][]float64, len(m))
+	for o, row := range m {
+		out[o] = make([]float64, n)
+		for i := 0; i < n; i++ {
+			var x float64
+			for c, k := range row {
+				x += k * in[c][i]
+			}
+			out[o][i] = w.clip(x)
+		}
+	}
+	w.SetChannels(out)
+}
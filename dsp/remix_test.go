@@ -0,0 +1,55 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRemixITU(t *testing.T) {
+	one := []float64{1000, 1000}
+	zero := []float64{0, 0}
+	// FL FR FC LFE BL BR
+	track := newTestWav(48000, one, zero, one, one, one, zero)
+	track.Remix(PresetMatrix("itu", 6, DefaultMixLevels))
+
+	if track.NumChannels() != 2 {
+		t.Fatalf("got %d channels, wanted 2", track.NumChannels())
+	}
+	chans := track.Channels()
+	c := 1000 * math.Pow(10, -3.0/20)
+	if got, want := chans[0], []float64{1000 + 2*c, 1000 + 2*c}; !floatSliceEqual(got, want) {
+		t.Errorf("left: got %f, wanted %f", got, want)
+	}
+	if got, want := chans[1], []float64{c, c}; !floatSliceEqual(got, want) {
+		t.Errorf("right: got %f, wanted %f", got, want)
+	}
+}
+
+func TestPresetMatrices(t *testing.T) {
+	loro := PresetMatrix("loro", 6, PresetMixLevels("loro"))
+	if got, want := loro[0][FC], math.Pow(10, -4.5/20); math.Abs(got-want) > tolerance {
+		t.Errorf("loro center: got %f, wanted %f", got, want)
+	}
+	if got, want := loro[0][BL], 0.5; math.Abs(got-want) > 0.01 {
+		t.Errorf("loro surround: got %f, wanted %f", got, want)
+	}
+	// Lt/Rt keeps the Pro Logic II surround coefficients whatever the surround level.
+	ltrt := PresetMatrix("ltrt", 6, MixLevels{Center: -3, Surround: -10, LFE: math.Inf(-1)})
+	if got, want := []float64{ltrt[0][BL], ltrt[0][BR], ltrt[1][BL], ltrt[1][BR]}, []float64{-0.8716, -0.4903, 0.4903, 0.8716}; !floatSliceEqual(got, want) {
+		t.Errorf("ltrt surrounds: got %f, wanted %f", got, want)
+	}
+}
+
+func TestMatrixNormalize(t *testing.T) {
+	m := PresetMatrix("itu", 6, DefaultMixLevels)
+	m.Normalize()
+	for _, row := range m {
+		var sum float64
+		for _, v := range row {
+			sum += math.Abs(v)
+		}
+		if sum > 1+tolerance {
+			t.Errorf("row %f sums to %f", row, sum)
+		}
+	}
+}
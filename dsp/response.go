@@ -0,0 +1,248 @@
+package dsp
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/cmplx"
+)
+
+// Coefficients is the transfer function of a filter,
+// H(z) = (B[0] + B[1]z^-1 + ...) / (A[0] + A[1]z^-1 + ...).
+// Cascades keep their second order sections, which Response evaluates one by one
+// as multiplying out high orders loses precision.
+type Coefficients struct {
+	B        []float64      `json:"b"`
+	A        []float64      `json:"a"`
+	Sections []Coefficients `json:"sections,omitempty"`
+}
+
+// coefficients returns the transfer function of the section.
+func (f biquad) coefficients() Coefficients {
+	return Coefficients{B: []float64{f.a1, f.a2, f.a3}, A: []float64{1, f.b1, f.b2}}
+}
+
+// RollingAvgCoefficients returns the transfer function of RollingAvgLowpass.
+func RollingAvgCoefficients(bandwidth int) Coefficients {
+	c := Coefficients{B: make([]float64, bandwidth), A: []float64{1}}
+	for i := range c.B {
+		c.B[i] = 1 / float64(bandwidth)
+	}
+	return c
+}
+
+// BiquadCoefficients returns the transfer function of Biquad at the given sample rate.
+func BiquadCoefficients(fc, lh, sampleRate int) Coefficients {
+	return biquadSection(fc, lh, float64(sampleRate)).coefficients()
+}
+
+// WindowedSincCoefficients returns the transfer function of WindowedSinc at the given sample rate.
+func WindowedSincCoefficients(cutoff, bandwidth, sampleRate int) Coefficients {
+	return Coefficients{B: sincKernel(cutoff, bandwidth, float64(sampleRate)), A: []float64{1}}
+}
+
+// HighpassCoefficients returns the transfer function of Highpass.
+func HighpassCoefficients() Coefficients {
+	return Coefficients{B: []float64{1, -2, 1}, A: []float64{1}}
+}
+
+// ChebyshevCoefficients returns the transfer function of Chebyshev at the given sample rate.
+func ChebyshevCoefficients(fc float64, lh int, ripple float64, poles, sampleRate int) Coefficients {
+	FC := fc / float64(sampleRate)
+	checkChebyshev(FC, ripple, poles)
+	return cascadeCoefficients(chebyshevSections(FC, float64(lh), ripple, float64(poles))...)
+}
+
+// cascadeCoefficients returns the transfer function of the sections in series.
+func cascadeCoefficients(sections ...biquad) Coefficients {
+	c := Coefficients{B: []float64{1}, A: []float64{1}}
+	for _, f := range sections {
+		fc := f.coefficients()
+		c.B = convolve(c.B, fc.B)
+		c.A = convolve(c.A, fc.A)
+		c.Sections = append(c.Sections, fc)
+	}
+	return c
+}
+
+// convolve returns the product of two polynomials.
+func convolve(p, q []float64) []float64 {
+	r := make([]float64, len(p)+len(q)-1)
+	for i, a := range p {
+		for j, b := range q {
+			r[i+j] += a * b
+		}
+	}
+	return r
+}
+
+// Response is the frequency response of a filter.
+// Magnitude is in dB, phase is unwrapped in radians and group delay is in samples.
+type Response struct {
+	Frequencies []float64 `json:"frequencies"`
+	Magnitude   []float64 `json:"magnitude"`
+	Phase       []float64 `json:"phase"`
+	GroupDelay  []float64 `json:"groupDelay"`
+}
+
+// evaluate returns the polynomial p(z^-1) and the sum of n*p[n]*z^-n at angular frequency omega.
+func evaluate(p []float64, omega float64) (complex128, complex128) {
+	var sum, ramp complex128
+	for n, v := range p {
+		z := cmplx.Exp(complex(0, -omega*float64(n)))
+		sum += complex(v, 0) * z
+		ramp += complex(v*float64(n), 0) * z
+	}
+	return sum, ramp
+}
+
+// at returns the transfer function and its group delay in samples at angular frequency omega.
+func (c Coefficients) at(omega float64) (complex128, float64) {
+	if len(c.Sections) > 0 {
+		h, gd := complex(1, 0), 0.0
+		for _, f := range c.Sections {
+			fh, fgd := f.at(omega)
+			h *= fh
+			gd += fgd
+		}
+		return h, gd
+	}
+	b, rb := evaluate(c.B, omega)
+	a, ra := evaluate(c.A, omega)
+
+	// The group delay of B/A is that of B less that of A, each Re(sum n*p[n]z^-n / p(z^-1)).
+	var gd float64
+	if cmplx.Abs(b) > 1e-12 {
+		gd += real(rb / b)
+	}
+	if cmplx.Abs(a) > 1e-12 {
+		gd -= real(ra / a)
+	}
+	return b / a, gd
+}
+
+// Response evaluates the transfer function at the given frequencies in Hz.
+func (c Coefficients) Response(sampleRate int, freqs []float64) Response {
+	r := Response{Frequencies: freqs}
+	var prev, offset float64
+	for i, f := range freqs {
+		h, gd := c.at(2 * math.Pi * f / float64(sampleRate))
+		r.Magnitude = append(r.Magnitude, math.Max(20*math.Log10(cmplx.Abs(h)), MinDBFS))
+
+		phase := cmplx.Phase(h)
+		if i > 0 {
+			for phase+offset-prev > math.Pi {
+				offset -= 2 * math.Pi
+			}
+			for phase+offset-prev < -math.Pi {
+				offset += 2 * math.Pi
+			}
+		}
+		prev = phase + offset
+		r.Phase = append(r.Phase, prev)
+		r.GroupDelay = append(r.GroupDelay, gd)
+	}
+	return r
+}
+
+// Frequencies returns n frequencies from low to high Hz, logarithmically spaced if logFreq is set.
+func Frequencies(n int, low, high float64, logFreq bool) []float64 {
+	freqs := make([]float64, n)
+	for i := range freqs {
+		t := float64(i) / math.Max(1, float64(n-1))
+		if logFreq {
+			freqs[i] = low * math.Pow(high/low, t)
+		} else {
+			freqs[i] = low + t*(high-low)
+		}
+	}
+	return freqs
+}
+
+var (
+	plotBackground = color.RGBA{255, 255, 255, 255}
+	plotGrid       = color.RGBA{220, 220, 220, 255}
+	plotMagnitude  = color.RGBA{25, 55, 140, 255}
+	plotPhase      = color.RGBA{212, 72, 66, 255}
+)
+
+// Image plots the magnitude response above the phase response.
+// Magnitude runs from floor to 12 dB with grid lines every 6 dB, phase has grid lines every pi/2.
+// Frequency grid lines are at every decade on a log axis and every tenth on a linear one.
+func (r Response) Image(width, height int, floor float64, logFreq bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetRGBA(x, y, plotBackground)
+		}
+	}
+	if len(r.Frequencies) < 2 {
+		return img
+	}
+	low, high := r.Frequencies[0], r.Frequencies[len(r.Frequencies)-1]
+	xOf := func(f float64) int {
+		if logFreq {
+			return int(float64(width-1) * math.Log(f/low) / math.Log(high/low))
+		}
+		return int(float64(width-1) * (f - low) / (high - low))
+	}
+
+	minPhase, maxPhase := r.Phase[0], r.Phase[0]
+	for _, p := range r.Phase {
+		minPhase = math.Min(minPhase, p)
+		maxPhase = math.Max(maxPhase, p)
+	}
+	minPhase = math.Floor(minPhase/(math.Pi/2)) * math.Pi / 2
+	maxPhase = math.Ceil(maxPhase/(math.Pi/2))*math.Pi/2 + math.Pi/2
+
+	panel := height / 2
+	yOf := func(v, lo, hi float64, top int) int {
+		v = math.Max(lo, math.Min(hi, v))
+		return top + int(float64(panel-1)*(hi-v)/(hi-lo))
+	}
+	hline := func(y int) {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, plotGrid)
+		}
+	}
+	for db := 12.0; db >= floor; db -= 6 {
+		hline(yOf(db, floor, 12, 0))
+	}
+	for p := minPhase; p <= maxPhase; p += math.Pi / 2 {
+		hline(yOf(p, minPhase, maxPhase, panel))
+	}
+	if logFreq {
+		for f := math.Pow(10, math.Ceil(math.Log10(low))); f <= high; f *= 10 {
+			for y := 0; y < height; y++ {
+				img.SetRGBA(xOf(f), y, plotGrid)
+			}
+		}
+	} else {
+		for i := 0; i <= 10; i++ {
+			for y := 0; y < height; y++ {
+				img.SetRGBA(xOf(low+float64(i)*(high-low)/10), y, plotGrid)
+			}
+		}
+	}
+
+	// trace joins consecutive points with vertical runs so steep slopes stay connected.
+	trace := func(values []float64, lo, hi float64, top int, c color.RGBA) {
+		for i := 1; i < len(values); i++ {
+			x0, x1 := xOf(r.Frequencies[i-1]), xOf(r.Frequencies[i])
+			y0, y1 := yOf(values[i-1], lo, hi, top), yOf(values[i], lo, hi, top)
+			for x := x0; x <= x1; x++ {
+				y := y0
+				if x1 > x0 {
+					y = y0 + (y1-y0)*(x-x0)/(x1-x0)
+				}
+				img.SetRGBA(x, y, c)
+			}
+			for y := int(math.Min(float64(y0), float64(y1))); y <= int(math.Max(float64(y0), float64(y1))); y++ {
+				img.SetRGBA(x1, y, c)
+			}
+		}
+	}
+	trace(r.Magnitude, floor, 12, 0, plotMagnitude)
+	trace(r.Phase, minPhase, maxPhase, panel, plotPhase)
+	return img
+}
@@ -0,0 +1,37 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBiquadResponse(t *testing.T) {
+	r := BiquadCoefficients(1000, 0, 48000).Response(48000, []float64{0, 1000, 10000})
+	for i, want := range []float64{0, -3.0103} {
+		if got := r.Magnitude[i]; math.Abs(got-want) > 0.01 {
+			t.Errorf("got %f dB at %f Hz, wanted %f", got, r.Frequencies[i], want)
+		}
+	}
+	if got := r.Phase[1]; math.Abs(got- -math.Pi/2) > 0.01 {
+		t.Errorf("got phase %f at cutoff, wanted %f", got, -math.Pi/2)
+	}
+}
+
+func TestResponseMatchesFilter(t *testing.T) {
+	// Filtering a sine must scale it by the magnitude the response reports.
+	track := newTestWav(48000, sine(48000, 3000, -6, 1))
+	track.Biquad(2000, 0)
+	want := -6 + BiquadCoefficients(2000, 0, 48000).Response(48000, []float64{3000}).Magnitude[0]
+	if got := track.toDBFS(rms(track.Channels()[0][4800:])) + 3.0103; math.Abs(got-want) > 0.05 {
+		t.Errorf("got %f dB, wanted %f", got, want)
+	}
+}
+
+func TestRollingAvgGroupDelay(t *testing.T) {
+	r := RollingAvgCoefficients(9).Response(48000, []float64{10, 1000})
+	for i, got := range r.GroupDelay {
+		if math.Abs(got-4) > 1e-6 {
+			t.Errorf("got group delay %f at %f Hz, wanted 4", got, r.Frequencies[i])
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package dsp
+
+import (
+	"math"
+)
+
+// rmsWindow is the length in seconds of the windows RMS is measured over.
+const rmsWindow = 0.05
+
+// RMS returns the RMS level of Wav in dBFS measured over short windows,
+// leaving out the windows quieter than the silence threshold in dBFS.
+func (w *Wav) RMS(silence float64) float64 {
+	nch := int(math.Max(1, float64(w.numChannels)))
+	size := nch * int(math.Max(1, math.Round(float64(w.sampleRate)*rmsWindow)))
+	var sum float64
+	var n int
+	for i := 0; i < len(w.data); i += size {
+		end := int(math.Min(float64(i+size), float64(len(w.data))))
+		r := rms(w.data[i:end])
+		if w.toDBFS(r) > silence {
+			sum += r * r
+			n++
+		}
+	}
+	if n == 0 {
+		return MinDBFS
+	}
+	return w.toDBFS(math.Sqrt(sum / float64(n)))
+}
+
+// NormalizeRMS normalizes a track according to the desired RMS level in dBFS,
+// ignoring windows quieter than the silence threshold in dBFS.
+func (w *Wav) NormalizeRMS(desiredRMS, silence float64) {
+	level := w.RMS(silence)
+	if level == MinDBFS {
+		return
+	}
+	w.amplify(math.Pow(10, (desiredRMS-level)/20))
+}
+
+// NormalizeCrest normalizes a track according to the desired RMS level in dBFS,
+// then limits peaks so they stay at most crest dB above it and below full scale.
+// Limiting lowers the RMS level, so the two are repeated until they settle.
+func (w *Wav) NormalizeCrest(desiredRMS, crest, silence float64) {
+	ceiling := math.Min(desiredRMS+crest, 0)
+	for i := 0; i < 8; i++ {
+		w.NormalizeRMS(desiredRMS, silence)
+		if w.toDBFS(w.peak()) <= ceiling {
+			return
+		}
+		w.Limit(ceiling, 5, 50)
+	}
+}
@@ -0,0 +1,31 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRMSIgnoresSilence(t *testing.T) {
+	x := append(sine(48000, 1000, -10, 1), make([]float64, 48000*4)...)
+	track := newTestWav(48000, x)
+	// A sine's RMS is 3 dB below its peak, the four seconds of silence must not pull it down.
+	if got, want := track.RMS(-60), -13.01; math.Abs(got-want) > 0.05 {
+		t.Errorf("got %f dBFS, wanted %f", got, want)
+	}
+
+	track.NormalizeRMS(-20, -60)
+	if got := track.RMS(-60); math.Abs(got - -20) > 0.05 {
+		t.Errorf("got %f dBFS after normalizing, wanted -20", got)
+	}
+}
+
+func TestNormalizeCrest(t *testing.T) {
+	x := sine(48000, 1000, -10, 1)
+	x[1000] = 30000
+	track := newTestWav(48000, x)
+	track.NormalizeCrest(-20, 6, -60)
+
+	if got := track.toDBFS(track.peak()) - track.RMS(-60); got > 6+0.05 {
+		t.Errorf("got crest factor %f dB, wanted at most 6", got)
+	}
+}
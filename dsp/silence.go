@@ -0,0 +1,98 @@
+package dsp
+
+import (
+	"math"
+)
+
+// Region is a span of a track between two times in seconds.
+type Region struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// Silences returns the regions of Wav where every channel stays below the threshold in dBFS
+// for at least minDuration seconds. Sound holds off silence for hold seconds after it,
+// so decays and short gaps between words are not cut into.
+func (w *Wav) Silences(threshold, minDuration, hold float64) []Region {
+	threshold = w.fromDBFS(threshold)
+	sr := float64(w.sampleRate)
+	nch := int(math.Max(1, float64(w.numChannels)))
+	frames := len(w.data) / nch
+	holdFrames := int(hold * sr)
+	minFrames := int(math.Max(1, minDuration*sr))
+
+	var regions []Region
+	lastLoud := -holdFrames - 1
+	silentFrom := 0
+	for i := 0; i <= frames; i++ {
+		loud := i == frames
+		for c := 0; c < nch && !loud; c++ {
+			loud = math.Abs(w.data[i*nch+c]) >= threshold
+		}
+		if loud {
+			// The silence ends at this frame, or where the hold of the last sound ran out.
+			from := int(math.Max(float64(silentFrom), float64(lastLoud+holdFrames+1)))
+			if silentFrom >= 0 && i-from >= minFrames {
+				regions = append(regions, Region{float64(from) / sr, float64(i) / sr})
+			}
+			lastLoud = i
+			silentFrom = -1
+		} else if silentFrom < 0 {
+			silentFrom = i
+		}
+	}
+	return regions
+}
+
+// Crop keeps only the samples of Wav between start and end seconds.
+// An end at or before start runs to the end of the track.
+func (w *Wav) Crop(start, end float64) {
+	w.SetChannels(w.Segment(start, end))
+}
+
+// Fade applies linear fades over the first fadeIn and last fadeOut seconds of Wav.
+func (w *Wav) Fade(fadeIn, fadeOut float64) {
+	sr := float64(w.sampleRate)
+	nch := int(math.Max(1, float64(w.numChannels)))
+	frames := len(w.data) / nch
+	in := int(fadeIn * sr)
+	out := int(fadeOut * sr)
+	for i := 0; i < frames; i++ {
+		g := 1.0
+		if i < in {
+			g = float64(i) / float64(in)
+		}
+		if frames-1-i < out {
+			g = math.Min(g, float64(frames-1-i)/float64(out))
+		}
+		for c := 0; c < nch; c++ {
+			w.data[i*nch+c] *= g
+		}
+	}
+}
+
+// Trim removes leading and trailing silence found by Silences from Wav, keeping pad seconds
+// of it on either side, then fades in and out over the given seconds.
+// It returns the region of the original track that was kept.
+func (w *Wav) Trim(threshold, minDuration, hold, pad, fadeIn, fadeOut float64) Region {
+	nch := int(math.Max(1, float64(w.numChannels)))
+	duration := float64(len(w.data)/nch) / float64(w.sampleRate)
+	kept := Region{0, duration}
+	for _, r := range w.Silences(threshold, minDuration, hold) {
+		if r.Start == 0 {
+			kept.Start = math.Max(0, r.End-pad)
+		}
+		if r.End >= duration {
+			kept.End = math.Min(duration, r.Start+pad)
+		}
+	}
+	if kept.End <= kept.Start {
+		// All silence, nothing to keep.
+		kept.End = kept.Start
+		w.SetChannels(make([][]float64, w.NumChannels()))
+		return kept
+	}
+	w.Crop(kept.Start, kept.End)
+	w.Fade(fadeIn, fadeOut)
+	return kept
+}
@@ -0,0 +1,35 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSilences(t *testing.T) {
+	// 1s silence, 1s tone, 0.05s gap, 1s tone, 1s silence.
+	x := make([]float64, 8000)
+	x = append(x, sine(8000, 440, -6, 1)...)
+	x = append(x, make([]float64, 400)...)
+	x = append(x, sine(8000, 440, -6, 1)...)
+	x = append(x, make([]float64, 8000)...)
+	track := newTestWav(8000, x, x)
+
+	got := track.Silences(-50, 0.5, 0.1)
+	want := []Region{{0, 1}, {3.05 + 0.1, 4.05}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, wanted %v", got, want)
+	}
+	for i := range got {
+		if math.Abs(got[i].Start-want[i].Start) > 0.01 || math.Abs(got[i].End-want[i].End) > 0.01 {
+			t.Errorf("got %v, wanted %v", got[i], want[i])
+		}
+	}
+
+	kept := track.Trim(-50, 0.5, 0.1, 0.2, 0.01, 0.01)
+	if math.Abs(kept.Start-0.8) > 0.01 || math.Abs(kept.End-3.35) > 0.01 {
+		t.Errorf("kept %v, wanted {0.8 3.35}", kept)
+	}
+	if got, want := track.Duration, kept.End-kept.Start; math.Abs(got-want) > 0.001 {
+		t.Errorf("got %fs after trimming, wanted %fs", got, want)
+	}
+}
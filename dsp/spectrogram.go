@@ -0,0 +1,106 @@
+package dsp
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Spectrogram holds the magnitude in dB of every frame of a short-time Fourier transform,
+// relative to a full scale sine.
+type Spectrogram struct {
+	Times       []float64   // Center of each frame in seconds
+	Frequencies []float64   // Center of each bin in Hz
+	Magnitudes  [][]float64 // Indexed by frame then bin
+}
+
+// STFT returns the short-time Fourier transform of Wav using frames of the given size every hop samples.
+// The power of all channels is averaged together.
+func (w *Wav) STFT(size, hop int, windowName string) Spectrogram {
+	win := windowFunc(windowName)(size)
+	var scale float64
+	for _, v := range win {
+		scale += v
+	}
+	scale *= math.Pow(2, float64(w.bitsPerSample-1)) / 2
+
+	var s Spectrogram
+	chans := w.Channels()
+	sr := float64(w.sampleRate)
+	for k := 0; k <= size/2; k++ {
+		s.Frequencies = append(s.Frequencies, float64(k)*sr/float64(size))
+	}
+	for i := 0; i == 0 || i+size <= len(chans[0]); i += hop {
+		power := make([]float64, size/2+1)
+		for _, ch := range chans {
+			frame := ch[i:int(math.Min(float64(i+size), float64(len(ch))))]
+			for k, p := range powerSpectrum(frame, win, scale) {
+				power[k] += p / float64(len(chans))
+			}
+		}
+		for k := range power {
+			power[k] = math.Max(10*math.Log10(power[k]), MinDBFS)
+		}
+		s.Times = append(s.Times, (float64(i)+float64(size)/2)/sr)
+		s.Magnitudes = append(s.Magnitudes, power)
+	}
+	return s
+}
+
+// colormap are the stops of the dB colormap, from the floor up to 0 dB.
+var colormap = []color.RGBA{
+	{0, 0, 4, 255},
+	{40, 11, 84, 255},
+	{101, 21, 110, 255},
+	{159, 42, 99, 255},
+	{212, 72, 66, 255},
+	{245, 125, 21, 255},
+	{250, 193, 39, 255},
+	{252, 255, 164, 255},
+}
+
+// colorAt returns the colormap color of v between 0 and 1.
+func colorAt(v float64) color.RGBA {
+	v = math.Max(0, math.Min(1, v)) * float64(len(colormap)-1)
+	i := int(v)
+	if i >= len(colormap)-1 {
+		return colormap[len(colormap)-1]
+	}
+	t := v - float64(i)
+	a, b := colormap[i], colormap[i+1]
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + t*(float64(y)-float64(x)))
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 255}
+}
+
+// Image renders the spectrogram with time left to right and frequency bottom to top.
+// A width of 0 uses one column per frame. Magnitudes from floor to 0 dB span the colormap,
+// and logFreq spaces the frequency axis logarithmically from the first bin above DC.
+func (s Spectrogram) Image(width, height int, floor float64, logFreq bool) image.Image {
+	if width <= 0 {
+		width = len(s.Times)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	bins := len(s.Frequencies) - 1
+	for x := 0; x < width; x++ {
+		frame := s.Magnitudes[x*len(s.Times)/width]
+		for y := 0; y < height; y++ {
+			pos := (float64(y) + 0.5) / float64(height)
+			var bin float64
+			if logFreq {
+				bin = math.Pow(float64(bins), pos)
+			} else {
+				bin = pos * float64(bins)
+			}
+			// Linear interpolation between the two nearest bins.
+			k := int(bin)
+			m := frame[k]
+			if k < bins {
+				m += (bin - float64(k)) * (frame[k+1] - frame[k])
+			}
+			img.SetRGBA(x, height-1-y, colorAt(1-m/floor))
+		}
+	}
+	return img
+}
@@ -0,0 +1,27 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSTFT(t *testing.T) {
+	track := newTestWav(8000, sine(8000, 1000, -6, 1))
+	s := track.STFT(256, 128, "hann")
+
+	if got, want := len(s.Times), (8000-256)/128+1; got != want {
+		t.Errorf("got %d frames, wanted %d", got, want)
+	}
+	if got, want := len(s.Frequencies), 129; got != want {
+		t.Errorf("got %d bins, wanted %d", got, want)
+	}
+	// 1 kHz falls exactly on bin 32.
+	if got := s.Magnitudes[10][32]; math.Abs(got - -6) > 0.1 {
+		t.Errorf("got %f dB at 1 kHz, wanted -6", got)
+	}
+
+	img := s.Image(0, 100, -120, true)
+	if b := img.Bounds(); b.Dx() != len(s.Times) || b.Dy() != 100 {
+		t.Errorf("got %dx%d image, wanted %dx100", b.Dx(), b.Dy(), len(s.Times))
+	}
+}
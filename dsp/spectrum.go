@@ -0,0 +1,133 @@
+package dsp
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"github.com/mjibson/go-dsp/fft"
+	"github.com/mjibson/go-dsp/window"
+)
+
+// Windows maps the names of the available window functions to their implementation.
+var Windows = map[string]func(int) []float64{
+	"rectangular": window.Rectangular,
+	"hann":        window.Hann,
+	"hamming":     window.Hamming,
+	"blackman":    window.Blackman,
+	"bartlett":    window.Bartlett,
+	"flattop":     window.FlatTop,
+}
+
+// Spectrum is an averaged magnitude spectrum in dB relative to a full scale sine.
+type Spectrum struct {
+	Frequencies []float64 `json:"frequencies"`
+	Magnitudes  []float64 `json:"magnitudes"`
+}
+
+// SpectralPeak is a local maximum of a Spectrum.
+type SpectralPeak struct {
+	Frequency float64 `json:"frequency"`
+	Magnitude float64 `json:"magnitude"`
+}
+
+// Segment returns the samples of each channel between start and end seconds.
+// An end at or before start runs to the end of the track.
+func (w *Wav) Segment(start, end float64) [][]float64 {
+	chans := w.Channels()
+	sr := float64(w.sampleRate)
+	for c, ch := range chans {
+		from := int(math.Max(0, math.Min(start*sr, float64(len(ch)))))
+		to := len(ch)
+		if end > start {
+			to = int(math.Min(end*sr, float64(len(ch))))
+		}
+		chans[c] = ch[from:to]
+	}
+	return chans
+}
+
+// windowFunc looks up a window function by name.
+func windowFunc(name string) func(int) []float64 {
+	wf, ok := Windows[name]
+	if !ok {
+		panic(fmt.Sprintf("unknown window: %s", name))
+	}
+	return wf
+}
+
+// powerSpectrum returns the power of each bin up to Nyquist of one windowed frame.
+// Frames shorter than the window are zero padded.
+func powerSpectrum(frame, win []float64, scale float64) []float64 {
+	x := make([]float64, len(win))
+	for i := range x {
+		if i < len(frame) {
+			x[i] = frame[i] * win[i] / scale
+		}
+	}
+	X := fft.FFTReal(x)
+	p := make([]float64, len(win)/2+1)
+	for k := range p {
+		p[k] = math.Pow(cmplx.Abs(X[k]), 2)
+	}
+	return p
+}
+
+// Spectrum returns the magnitude spectrum of Wav between start and end seconds,
+// averaged over half overlapping frames of the given size and over all channels.
+func (w *Wav) Spectrum(size int, windowName string, start, end float64) Spectrum {
+	win := windowFunc(windowName)(size)
+	// Scale so a full scale sine reads 0 dB.
+	var scale float64
+	for _, v := range win {
+		scale += v
+	}
+	scale *= math.Pow(2, float64(w.bitsPerSample-1)) / 2
+
+	power := make([]float64, size/2+1)
+	var frames int
+	for _, ch := range w.Segment(start, end) {
+		for i := 0; i == 0 || i+size <= len(ch); i += size / 2 {
+			frame := ch[i:int(math.Min(float64(i+size), float64(len(ch))))]
+			for k, p := range powerSpectrum(frame, win, scale) {
+				power[k] += p
+			}
+			frames++
+		}
+	}
+
+	s := Spectrum{
+		Frequencies: make([]float64, len(power)),
+		Magnitudes:  make([]float64, len(power)),
+	}
+	for k := range power {
+		s.Frequencies[k] = float64(k) * float64(w.sampleRate) / float64(size)
+		s.Magnitudes[k] = math.Max(10*math.Log10(power[k]/float64(frames)), MinDBFS)
+	}
+	return s
+}
+
+// Peaks returns up to n local maxima of the spectrum, strongest first.
+// The frequency and magnitude of each are refined by parabolic interpolation between bins.
+func (s Spectrum) Peaks(n int) []SpectralPeak {
+	var peaks []SpectralPeak
+	m := s.Magnitudes
+	for k := 1; k < len(m)-1; k++ {
+		if m[k] <= m[k-1] || m[k] < m[k+1] || m[k] == MinDBFS {
+			continue
+		}
+		d := 0.5 * (m[k-1] - m[k+1]) / (m[k-1] - 2*m[k] + m[k+1])
+		peaks = append(peaks, SpectralPeak{
+			Frequency: s.Frequencies[k] + d*(s.Frequencies[1]-s.Frequencies[0]),
+			Magnitude: m[k] - 0.25*(m[k-1]-m[k+1])*d,
+		})
+	}
+	sort.Slice(peaks, func(i, j int) bool {
+		return peaks[i].Magnitude > peaks[j].Magnitude
+	})
+	if len(peaks) > n {
+		peaks = peaks[:n]
+	}
+	return peaks
+}
@@ -0,0 +1,31 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpectrumPeak(t *testing.T) {
+	track := newTestWav(48000, sine(48000, 1234, -6, 1))
+	peaks := track.Spectrum(4096, "hann", 0, 0).Peaks(1)
+
+	if len(peaks) != 1 {
+		t.Fatalf("got %d peaks, wanted 1", len(peaks))
+	}
+	if got := peaks[0].Frequency; math.Abs(got-1234) > 2 {
+		t.Errorf("got peak at %f Hz, wanted 1234", got)
+	}
+	if got := peaks[0].Magnitude; math.Abs(got - -6) > 0.5 {
+		t.Errorf("got peak of %f dB, wanted -6", got)
+	}
+}
+
+func TestSegment(t *testing.T) {
+	track := newTestWav(10, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	if got, want := track.Segment(0.2, 0.5)[0], []float64{2, 3, 4}; !floatSliceEqual(got, want) {
+		t.Errorf("got %f, wanted %f", got, want)
+	}
+	if got, want := track.Segment(0.8, 0)[0], []float64{8, 9}; !floatSliceEqual(got, want) {
+		t.Errorf("got %f, wanted %f", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package dsp
+
+import (
+	"math"
+	"sort"
+)
+
+// Cues returns the times in seconds of the cue markers read from the cue chunk of Wav, in order.
+func (w *Wav) Cues() []float64 {
+	var cues []float64
+	for _, c := range w.cues {
+		cues = append(cues, float64(c)/float64(w.sampleRate))
+	}
+	sort.Float64s(cues)
+	return cues
+}
+
+// SilenceSplits returns split points in the middle of every silence found by Silences
+// that lies between sounds. Leading and trailing silence is left on the first and last pieces.
+func (w *Wav) SilenceSplits(threshold, minDuration, hold float64) []float64 {
+	nch := int(math.Max(1, float64(w.numChannels)))
+	duration := float64(len(w.data)/nch) / float64(w.sampleRate)
+	var points []float64
+	for _, r := range w.Silences(threshold, minDuration, hold) {
+		if r.Start > 0 && r.End < duration {
+			points = append(points, (r.Start+r.End)/2)
+		}
+	}
+	return points
+}
+
+// EverySplits returns split points every given number of seconds of Wav.
+func (w *Wav) EverySplits(every float64) []float64 {
+	nch := int(math.Max(1, float64(w.numChannels)))
+	duration := float64(len(w.data)/nch) / float64(w.sampleRate)
+	var points []float64
+	if every <= 0 {
+		return points
+	}
+	for t := every; t < duration; t += every {
+		points = append(points, t)
+	}
+	return points
+}
+
+// Split cuts Wav at the given points in seconds and returns the pieces with their regions
+// of the original track. Points are sorted and those outside the track or repeated are ignored.
+func (w *Wav) Split(points []float64) ([]*Wav, []Region) {
+	nch := int(math.Max(1, float64(w.numChannels)))
+	duration := float64(len(w.data)/nch) / float64(w.sampleRate)
+	sorted := append([]float64{}, points...)
+	sort.Float64s(sorted)
+
+	bounds := []float64{0}
+	for _, p := range sorted {
+		if p > bounds[len(bounds)-1] && p < duration {
+			bounds = append(bounds, p)
+		}
+	}
+	bounds = append(bounds, duration)
+
+	var pieces []*Wav
+	var regions []Region
+	for i := 1; i < len(bounds); i++ {
+		piece := *w
+		piece.data = nil
+		piece.cues = nil
+		piece.SetChannels(w.Segment(bounds[i-1], bounds[i]))
+		pieces = append(pieces, &piece)
+		regions = append(regions, Region{bounds[i-1], bounds[i]})
+	}
+	return pieces, regions
+}
@@ -0,0 +1,122 @@
+package dsp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	// 1s tone, 0.5s gap, 1s tone, 0.5s gap, 1s tone.
+	var x []float64
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			x = append(x, make([]float64, 4000)...)
+		}
+		x = append(x, sine(8000, 440, -6, 1)...)
+	}
+	track := newTestWav(8000, x, x)
+
+	points := track.SilenceSplits(-50, 0.2, 0.05)
+	if len(points) != 2 {
+		t.Fatalf("got split points %v, wanted 2", points)
+	}
+	pieces, regions := track.Split(points)
+	if len(pieces) != 3 {
+		t.Fatalf("got %d pieces, wanted 3", len(pieces))
+	}
+	var frames int
+	for i, p := range pieces {
+		frames += len(p.data) / 2
+		if got, want := p.Duration, regions[i].End-regions[i].Start; math.Abs(got-want) > 0.001 {
+			t.Errorf("piece %d is %fs, wanted %fs", i, got, want)
+		}
+	}
+	if frames != len(x) {
+		t.Errorf("got %d frames in pieces, wanted %d", frames, len(x))
+	}
+
+	if got, want := track.EverySplits(1.5), []float64{1.5, 3}; !floatSliceEqual(got, want) {
+		t.Errorf("got %v, wanted %v", got, want)
+	}
+}
+
+func TestReadChunks(t *testing.T) {
+	var b bytes.Buffer
+	chunk := func(id string, v ...interface{}) {
+		var body bytes.Buffer
+		for _, f := range v {
+			binary.Write(&body, binary.LittleEndian, f)
+		}
+		b.WriteString(id)
+		binary.Write(&b, binary.LittleEndian, uint32(body.Len()))
+		b.Write(body.Bytes())
+		if body.Len()%2 == 1 {
+			b.WriteByte(0)
+		}
+	}
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(0))
+	b.WriteString("WAVE")
+	// Extensible format with an extension, an odd sized chunk to skip, then data and cues.
+	chunk("fmt ", uint16(0xFFFE), uint16(1), uint32(8000), uint32(16000), uint16(2), uint16(16),
+		uint16(22), uint16(16), uint32(4), uint16(1), make([]byte, 14))
+	chunk("LIST", []byte("odd"))
+	chunk("data", []int16{1, -2, 3, -4})
+	chunk("cue ", uint32(2),
+		uint32(1), uint32(0), []byte("data"), uint32(0), uint32(0), uint32(3),
+		uint32(2), uint32(0), []byte("data"), uint32(0), uint32(0), uint32(1))
+
+	track := NewWav()
+	if err := track.Read(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := track.data, []float64{1, -2, 3, -4}; !floatSliceEqual(got, want) {
+		t.Errorf("got %f, wanted %f", got, want)
+	}
+	if track.audioFormat != 1 || track.subchunk1Size != 16 || track.chunkSize != 36+8 {
+		t.Errorf("got format %d, fmt size %d, RIFF size %d", track.audioFormat, track.subchunk1Size, track.chunkSize)
+	}
+	if got, want := track.Cues(), []float64{1.0 / 8000, 3.0 / 8000}; !floatSliceEqual(got, want) {
+		t.Errorf("got cues %f, wanted %f", got, want)
+	}
+}
+
+func TestReadMissingFmt(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("RIFFxxxxWAVEdata")
+	binary.Write(&b, binary.LittleEndian, uint32(4))
+	binary.Write(&b, binary.LittleEndian, []int16{1, -1})
+	if err := NewWav().Read(&b); err == nil {
+		t.Error("got no error for data before fmt")
+	}
+	if err := NewWav().Read(bytes.NewBufferString("RIFFxxxxWAVE")); err == nil {
+		t.Error("got no error for a missing fmt chunk")
+	}
+}
+
+func TestReadFloat(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("RIFFxxxxWAVEfmt ")
+	binary.Write(&b, binary.LittleEndian, []uint32{16, 3 | 1<<16, 8000, 32000, 4 | 32<<16})
+	if err := NewWav().Read(&b); err == nil {
+		t.Error("got no error for IEEE float samples")
+	}
+}
+
+func TestReadMalformedFmt(t *testing.T) {
+	cases := map[string][]uint32{
+		"short fmt":   {8, 1 | 1<<16, 8000},
+		"no channels": {16, 1, 8000, 16000, 2 | 16<<16},
+		"no bits":     {16, 1 | 1<<16, 8000, 16000, 2},
+	}
+	for name, fmtChunk := range cases {
+		var b bytes.Buffer
+		b.WriteString("RIFFxxxxWAVEfmt ")
+		binary.Write(&b, binary.LittleEndian, fmtChunk)
+		if err := NewWav().Read(&b); err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+	}
+}
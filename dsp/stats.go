@@ -0,0 +1,64 @@
+package dsp
+
+import (
+	"math"
+	"sort"
+)
+
+// ChannelStats holds the level and quality statistics of a single channel.
+// Levels are in dBFS, dynamic range and crest factor in dB and the zero-crossing rate in crossings per second.
+// The noise floor is the 10th percentile and dynamic range the spread to the 95th percentile of the RMS of short windows.
+type ChannelStats struct {
+	DCOffset         float64 `json:"dcOffset"` // Mean sample value as a fraction of full scale
+	Peak             float64 `json:"peak"`
+	PeakDBFS         float64 `json:"peakDBFS"`
+	RMS              float64 `json:"rms"`
+	RMSDBFS          float64 `json:"rmsDBFS"`
+	CrestFactor      float64 `json:"crestFactor"`
+	DynamicRange     float64 `json:"dynamicRange"`
+	NoiseFloor       float64 `json:"noiseFloor"`
+	ZeroCrossingRate float64 `json:"zeroCrossingRate"`
+	Clipped          int     `json:"clipped"`
+}
+
+// Stats returns the statistics of every channel of Wav.
+// Samples at either end of the sample range count as clipped.
+func (w *Wav) Stats() []ChannelStats {
+	full := math.Pow(2, float64(w.bitsPerSample-1))
+	size := int(math.Max(1, math.Round(float64(w.sampleRate)*rmsWindow)))
+	var stats []ChannelStats
+	for _, ch := range w.Channels() {
+		var s ChannelStats
+		if len(ch) == 0 {
+			s.PeakDBFS, s.RMSDBFS, s.NoiseFloor = MinDBFS, MinDBFS, MinDBFS
+			stats = append(stats, s)
+			continue
+		}
+		s.DCOffset = avg(ch) / full
+		for i, x := range ch {
+			s.Peak = math.Max(s.Peak, math.Abs(x))
+			if x >= full-1 || x <= -full {
+				s.Clipped++
+			}
+			if i > 0 && (x >= 0) != (ch[i-1] >= 0) {
+				s.ZeroCrossingRate++
+			}
+		}
+		s.ZeroCrossingRate *= float64(w.sampleRate) / float64(len(ch))
+		s.RMS = rms(ch)
+		s.PeakDBFS = w.toDBFS(s.Peak)
+		s.RMSDBFS = w.toDBFS(s.RMS)
+		s.CrestFactor = s.PeakDBFS - s.RMSDBFS
+
+		var levels []float64
+		for i := 0; i < len(ch); i += size {
+			end := int(math.Min(float64(i+size), float64(len(ch))))
+			levels = append(levels, w.toDBFS(rms(ch[i:end])))
+		}
+		sort.Float64s(levels)
+		s.NoiseFloor = levels[int(math.Round(float64(len(levels)-1)*0.10))]
+		s.DynamicRange = levels[int(math.Round(float64(len(levels)-1)*0.95))] - s.NoiseFloor
+		stats = append(stats, s)
+	}
+	return stats
+}
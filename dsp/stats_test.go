@@ -0,0 +1,40 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	// 1s of a 100 Hz sine at -6 dBFS with a DC offset, then 1s of a quiet 1 kHz tone.
+	x := sine(8000, 100, -6, 1)
+	for i := range x {
+		x[i] += 0.01 * 32768
+	}
+	x = append(x, sine(8000, 1000, -60, 1)...)
+	clipped := make([]float64, len(x))
+	clipped[10], clipped[20] = 32767, -32768
+	track := newTestWav(8000, x, clipped)
+	stats := track.Stats()
+
+	s := stats[0]
+	if math.Abs(s.DCOffset-0.005) > 0.001 {
+		t.Errorf("got DC offset %f, wanted 0.005", s.DCOffset)
+	}
+	if math.Abs(s.NoiseFloor - -63.01) > 0.5 {
+		t.Errorf("got noise floor %f dBFS, wanted -63", s.NoiseFloor)
+	}
+	if math.Abs(s.DynamicRange-54) > 1 {
+		t.Errorf("got dynamic range %f dB, wanted 54", s.DynamicRange)
+	}
+	if math.Abs(s.CrestFactor-(s.PeakDBFS-s.RMSDBFS)) > 1e-9 || s.CrestFactor < 3 {
+		t.Errorf("got crest factor %f dB", s.CrestFactor)
+	}
+	// 200 crossings from the first second, 2000 from the second, over two seconds.
+	if math.Abs(s.ZeroCrossingRate-1100) > 5 {
+		t.Errorf("got zero-crossing rate %f, wanted 1100", s.ZeroCrossingRate)
+	}
+	if s.Clipped != 0 || stats[1].Clipped != 2 {
+		t.Errorf("got %d and %d clipped samples, wanted 0 and 2", s.Clipped, stats[1].Clipped)
+	}
+}
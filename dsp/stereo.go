@@ -0,0 +1,153 @@
+package dsp
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// stereoFloor is the level in dBFS below which windows are too quiet to judge stereo compatibility.
+const stereoFloor = -60.0
+
+// StereoFrame is the stereo image of one window.
+// Correlation runs from -1 (out of phase) through 0 (unrelated) to 1 (mono).
+// Balance is the level of left over right and MidSide the level of mid over side, both in dB.
+type StereoFrame struct {
+	Time        float64 `json:"time"`
+	Correlation float64 `json:"correlation"`
+	Balance     float64 `json:"balance"`
+	MidSide     float64 `json:"midSide"`
+}
+
+// StereoAnalysis is the stereo image of a whole track, its windows, and the regions where
+// the correlation falls below the threshold and the mix would suffer in mono.
+// MonoLoss is the drop in level in dB when the channels are summed to mono.
+type StereoAnalysis struct {
+	Correlation      float64       `json:"correlation"`
+	Balance          float64       `json:"balance"`
+	MidSide          float64       `json:"midSide"`
+	MonoLoss         float64       `json:"monoLoss"`
+	PolarityInverted bool          `json:"polarityInverted"`
+	MonoProblems     []Region      `json:"monoProblems"`
+	Frames           []StereoFrame `json:"frames"`
+}
+
+// stereoImage returns the correlation, balance and mid/side ratio from the channel energies
+// and their cross product.
+func stereoImage(ll, rr, lr float64) (float64, float64, float64) {
+	ratio := func(a, b float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		return math.Max(MinDBFS, math.Min(-MinDBFS, 10*math.Log10(a/b)))
+	}
+	var corr float64
+	if ll > 0 && rr > 0 {
+		corr = lr / math.Sqrt(ll*rr)
+	}
+	mid := (ll + rr + 2*lr) / 2
+	side := math.Max(0, (ll+rr-2*lr)/2)
+	return corr, ratio(ll, rr), ratio(mid, side)
+}
+
+// StereoAnalysis measures the stereo image of the first two channels of Wav over windows of the given
+// length in seconds. Windows louder than -60 dBFS with a correlation below threshold are reported
+// as mono compatibility problems, and an overall correlation below -0.5 as inverted polarity.
+func (w *Wav) StereoAnalysis(window, threshold float64) StereoAnalysis {
+	chans := w.Channels()
+	if len(chans) < 2 {
+		chans = append(chans, chans[0])
+	}
+	l, r := chans[0], chans[1]
+	size := int(math.Max(1, window*float64(w.sampleRate)))
+	floor := math.Pow(w.fromDBFS(stereoFloor), 2)
+
+	var a StereoAnalysis
+	var ll, rr, lr float64
+	problem := -1.0
+	for i := 0; i < len(l); i += size {
+		end := int(math.Min(float64(i+size), float64(len(l))))
+		var wl, wr, wlr float64
+		for j := i; j < end; j++ {
+			wl += l[j] * l[j]
+			wr += r[j] * r[j]
+			wlr += l[j] * r[j]
+		}
+		ll, rr, lr = ll+wl, rr+wr, lr+wlr
+		f := StereoFrame{Time: float64(i) / float64(w.sampleRate)}
+		f.Correlation, f.Balance, f.MidSide = stereoImage(wl, wr, wlr)
+		a.Frames = append(a.Frames, f)
+
+		bad := (wl+wr)/float64(2*(end-i)) > floor && f.Correlation < threshold
+		if bad && problem < 0 {
+			problem = f.Time
+		} else if !bad && problem >= 0 {
+			a.MonoProblems = append(a.MonoProblems, Region{problem, f.Time})
+			problem = -1
+		}
+	}
+	if problem >= 0 {
+		a.MonoProblems = append(a.MonoProblems, Region{problem, float64(len(l)) / float64(w.sampleRate)})
+	}
+
+	a.Correlation, a.Balance, a.MidSide = stereoImage(ll, rr, lr)
+	// The mono sum (L+R)/2 against the average power of the two channels.
+	if ll+rr > 0 {
+		a.MonoLoss = -math.Max(MinDBFS, 10*math.Log10((ll+rr+2*lr)/2/(ll+rr)))
+	}
+	a.PolarityInverted = a.Correlation < -0.5
+	return a
+}
+
+// Lissajous plots the first two channels of Wav against each other as a vectorscope, rotated so that
+// mono content is vertical and out of phase content horizontal. Brighter pixels are hit more often.
+func (w *Wav) Lissajous(size int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			img.SetRGBA(x, y, plotBackground)
+		}
+	}
+	// Axes for mono, side, and left and right only.
+	for i := 0; i < size; i++ {
+		img.SetRGBA(size/2, i, plotGrid)
+		img.SetRGBA(i, size/2, plotGrid)
+		img.SetRGBA(i, i, plotGrid)
+		img.SetRGBA(i, size-1-i, plotGrid)
+	}
+
+	chans := w.Channels()
+	if len(chans) < 2 {
+		chans = append(chans, chans[0])
+	}
+	full := math.Pow(2, float64(w.bitsPerSample-1)) * math.Sqrt2
+	hits := make([]int, size*size)
+	var most int
+	for i := range chans[0] {
+		l, r := chans[0][i], chans[1][i]
+		x := int(float64(size-1) * (0.5 + (r-l)/full/2))
+		y := int(float64(size-1) * (0.5 - (l+r)/full/2))
+		if x < 0 || x >= size || y < 0 || y >= size {
+			continue
+		}
+		hits[y*size+x]++
+		if hits[y*size+x] > most {
+			most = hits[y*size+x]
+		}
+	}
+	for p, n := range hits {
+		if n == 0 {
+			continue
+		}
+		// Log scaled so single hits stay visible next to dense areas.
+		t := math.Log1p(float64(n)) / math.Log1p(float64(most))
+		t = 0.35 + 0.65*t
+		img.SetRGBA(p%size, p/size, color.RGBA{
+			uint8(float64(plotBackground.R) + t*(float64(plotMagnitude.R)-float64(plotBackground.R))),
+			uint8(float64(plotBackground.G) + t*(float64(plotMagnitude.G)-float64(plotBackground.G))),
+			uint8(float64(plotBackground.B) + t*(float64(plotMagnitude.B)-float64(plotBackground.B))),
+			255,
+		})
+	}
+	return img
+}
@@ -0,0 +1,57 @@
+package dsp
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestStereoAnalysis(t *testing.T) {
+	x := sine(8000, 440, -6, 1)
+	inverted := make([]float64, len(x))
+	half := make([]float64, len(x))
+	noise := make([]float64, len(x))
+	rng := rand.New(rand.NewSource(1))
+	for i := range x {
+		inverted[i] = -x[i]
+		half[i] = x[i] / 2
+		noise[i] = 8000 * rng.NormFloat64()
+	}
+
+	a := newTestWav(8000, x, x).StereoAnalysis(0.1, 0)
+	if a.Correlation != 1 || a.Balance != 0 || a.MidSide != -MinDBFS || a.MonoLoss != 0 || len(a.MonoProblems) != 0 {
+		t.Errorf("got %+v for mono", a)
+	}
+	if len(a.Frames) != 10 {
+		t.Errorf("got %d frames, wanted 10", len(a.Frames))
+	}
+
+	a = newTestWav(8000, x, inverted).StereoAnalysis(0.1, 0)
+	if a.Correlation != -1 || !a.PolarityInverted || len(a.MonoProblems) != 1 || a.MonoProblems[0] != (Region{0, 1}) {
+		t.Errorf("got correlation %f, inverted %v and problems %v for inverted polarity", a.Correlation, a.PolarityInverted, a.MonoProblems)
+	}
+
+	if a = newTestWav(8000, x, half).StereoAnalysis(0.1, 0); math.Abs(a.Balance-6.02) > 0.01 {
+		t.Errorf("got balance %f dB, wanted 6.02", a.Balance)
+	}
+
+	if a = newTestWav(8000, x, noise).StereoAnalysis(0.1, 0); math.Abs(a.Correlation) > 0.05 || math.Abs(a.MonoLoss-3.01) > 0.3 {
+		t.Errorf("got correlation %f and mono loss %f dB for unrelated channels, wanted 0 and 3", a.Correlation, a.MonoLoss)
+	}
+}
+
+func TestLissajous(t *testing.T) {
+	x := sine(8000, 440, -6, 1)
+	img := newTestWav(8000, x, x).Lissajous(101)
+	// A mono track only draws on the vertical axis.
+	for px := 0; px < 101; px++ {
+		for py := 0; py < 101; py++ {
+			if c := img.At(px, py); c != plotBackground && c != plotGrid && px != 50 {
+				t.Fatalf("got a point at %d,%d off the mono axis", px, py)
+			}
+		}
+	}
+	if img.At(50, 50) == plotGrid {
+		t.Errorf("got nothing drawn at the centre")
+	}
+}
@@ -0,0 +1,50 @@
+package dsp
+
+import (
+	"math"
+)
+
+// truePeakPhases are the four 12-tap polyphase interpolation filters of ITU-R BS.1770-4 Annex 2.
+var truePeakPhases = [4][12]float64{
+	{0.0017089843750, 0.0109863281250, -0.0196533203125, 0.0332031250000, -0.0594482421875, 0.1373291015625,
+		0.9721679687500, -0.1022949218750, 0.0476074218750, -0.0266113281250, 0.0148925781250, -0.0083007812500},
+	{-0.0291748046875, 0.0292968750000, -0.0517578125000, 0.0891113281250, -0.1665039062500, 0.4650878906250,
+		0.7797851562500, -0.2003173828125, 0.1015625000000, -0.0582275390625, 0.0330810546875, -0.0189208984375},
+	{-0.0189208984375, 0.0330810546875, -0.0582275390625, 0.1015625000000, -0.2003173828125, 0.7797851562500,
+		0.4650878906250, -0.1665039062500, 0.0891113281250, -0.0517578125000, 0.0292968750000, -0.0291748046875},
+	{-0.0083007812500, 0.0148925781250, -0.0266113281250, 0.0476074218750, -0.1022949218750, 0.9721679687500,
+		0.1373291015625, -0.0594482421875, 0.0332031250000, -0.0196533203125, 0.0109863281250, 0.0017089843750},
+}
+
+// truePeak returns the largest magnitude of x after 4x oversampling.
+func truePeak(x []float64) float64 {
+	var peak float64
+	for n := range x {
+		peak = math.Max(peak, math.Abs(x[n]))
+		for _, h := range truePeakPhases {
+			var y float64
+			for j, k := range h {
+				if n-j >= 0 {
+					y += k * x[n-j]
+				}
+			}
+			peak = math.Max(peak, math.Abs(y))
+		}
+	}
+	return peak
+}
+
+// TruePeak returns the largest 4x oversampled true-peak magnitude over all channels of Wav.
+func (w *Wav) TruePeak() float64 {
+	var peak float64
+	for _, ch := range w.Channels() {
+		peak = math.Max(peak, truePeak(ch))
+	}
+	return peak
+}
+
+// NormalizeTruePeak normalizes a track according to the desired true peak in dBTP.
+func (w *Wav) NormalizeTruePeak(desiredPeak float64) {
+	base := w.fromDBFS(desiredPeak)
+	w.amplify(base / w.TruePeak())
+}
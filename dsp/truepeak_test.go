@@ -0,0 +1,22 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+// A quarter sample rate sine sampled 45 degrees off its peaks hides 3 dB of overs between samples.
+func TestTruePeakIntersample(t *testing.T) {
+	x := make([]float64, 4800)
+	for i := range x {
+		x[i] = 16384 * math.Sin(math.Pi/2*float64(i)+math.Pi/4)
+	}
+	track := newTestWav(48000, x)
+
+	if got, want := track.toDBFS(track.peak()), -9.03; math.Abs(got-want) > 0.05 {
+		t.Errorf("got sample peak %f dBFS, wanted %f", got, want)
+	}
+	if got, want := track.toDBFS(track.TruePeak()), -6.02; math.Abs(got-want) > 0.3 {
+		t.Errorf("got true peak %f dBTP, wanted %f", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package dsp
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+)
+
+// Waveform is an overview of each channel of a track with one column per pixel.
+// Values are relative to full scale, between -1 and 1.
+type Waveform struct {
+	Min [][]float64 // Indexed by channel then column
+	Max [][]float64
+	RMS [][]float64
+}
+
+// Waveform returns the minimum, maximum and RMS of each channel of Wav
+// over the given number of columns between start and end seconds.
+func (w *Wav) Waveform(columns int, start, end float64) Waveform {
+	fullScale := math.Pow(2, float64(w.bitsPerSample-1))
+	var wf Waveform
+	for _, ch := range w.Segment(start, end) {
+		min := make([]float64, columns)
+		max := make([]float64, columns)
+		env := make([]float64, columns)
+		for c := 0; c < columns; c++ {
+			from := c * len(ch) / columns
+			to := (c + 1) * len(ch) / columns
+			if from == to {
+				continue
+			}
+			min[c], max[c] = ch[from], ch[from]
+			for _, x := range ch[from:to] {
+				min[c] = math.Min(min[c], x)
+				max[c] = math.Max(max[c], x)
+			}
+			min[c] /= fullScale
+			max[c] /= fullScale
+			env[c] = rms(ch[from:to]) / fullScale
+		}
+		wf.Min = append(wf.Min, min)
+		wf.Max = append(wf.Max, max)
+		wf.RMS = append(wf.RMS, env)
+	}
+	return wf
+}
+
+var (
+	waveBackground = color.RGBA{255, 255, 255, 255}
+	waveAxis       = color.RGBA{200, 200, 200, 255}
+	wavePeak       = color.RGBA{100, 149, 237, 255}
+	waveRMS        = color.RGBA{25, 55, 140, 255}
+)
+
+// waveScale maps a value between -1 and 1 onto the vertical axis, between -1 and 1.
+// On a dB scale the magnitude runs from floor dB at the center line to 0 dB at the edge.
+func waveScale(v float64, db bool, floor float64) float64 {
+	if !db {
+		return v
+	}
+	if v == 0 {
+		return 0
+	}
+	m := math.Max(0, 1-20*math.Log10(math.Abs(v))/floor)
+	if v < 0 {
+		return -m
+	}
+	return m
+}
+
+// lane returns the pixel row of value v in the lane of channel ch.
+func (wf Waveform) lane(v float64, ch, height int, db bool, floor float64) float64 {
+	h := float64(height) / float64(len(wf.Min))
+	return h*float64(ch) + h/2*(1-waveScale(v, db, floor))
+}
+
+// Image renders the waveform with each channel in its own lane, the peaks drawn
+// around the darker RMS envelope. The width is the number of columns of the waveform.
+func (wf Waveform) Image(height int, db bool, floor float64) image.Image {
+	width := 0
+	if len(wf.Min) > 0 {
+		width = len(wf.Min[0])
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetRGBA(x, y, waveBackground)
+		}
+	}
+	vline := func(x int, y0, y1 float64, c color.RGBA) {
+		for y := int(math.Floor(y0)); y <= int(math.Ceil(y1)) && y < height; y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	for ch := range wf.Min {
+		center := wf.lane(0, ch, height, db, floor)
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, int(center), waveAxis)
+			vline(x, wf.lane(wf.Max[ch][x], ch, height, db, floor), wf.lane(wf.Min[ch][x], ch, height, db, floor), wavePeak)
+			vline(x, wf.lane(wf.RMS[ch][x], ch, height, db, floor), wf.lane(-wf.RMS[ch][x], ch, height, db, floor), waveRMS)
+		}
+	}
+	return img
+}
+
+// WriteSVG renders the waveform like Image as an SVG with filled outlines.
+func (wf Waveform) WriteSVG(out io.Writer, height int, db bool, floor float64) error {
+	width := 0
+	if len(wf.Min) > 0 {
+		width = len(wf.Min[0])
+	}
+	hex := func(c color.RGBA) string {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	b := bufio.NewWriter(out)
+	// outline draws the polygon between the upper and lower values of each column.
+	outline := func(ch int, upper, lower []float64, c color.RGBA) {
+		fmt.Fprint(b, "<polygon points=\"")
+		for x := 0; x < width; x++ {
+			fmt.Fprintf(b, "%d,%.2f ", x, wf.lane(upper[x], ch, height, db, floor))
+		}
+		for x := width - 1; x >= 0; x-- {
+			fmt.Fprintf(b, "%d,%.2f ", x, wf.lane(lower[x], ch, height, db, floor))
+		}
+		fmt.Fprintf(b, "\" fill=\"%s\"/>\n", hex(c))
+	}
+
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\">\n", width, height)
+	fmt.Fprintf(b, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", hex(waveBackground))
+	for ch := range wf.Min {
+		center := wf.lane(0, ch, height, db, floor)
+		fmt.Fprintf(b, "<line x1=\"0\" y1=\"%.2f\" x2=\"%d\" y2=\"%.2f\" stroke=\"%s\"/>\n", center, width, center, hex(waveAxis))
+		negRMS := make([]float64, width)
+		for x := range negRMS {
+			negRMS[x] = -wf.RMS[ch][x]
+		}
+		outline(ch, wf.Max[ch], wf.Min[ch], wavePeak)
+		outline(ch, wf.RMS[ch], negRMS, waveRMS)
+	}
+	fmt.Fprint(b, "</svg>\n")
+	return b.Flush()
+}
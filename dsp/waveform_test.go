@@ -0,0 +1,27 @@
+package dsp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWaveform(t *testing.T) {
+	track := newTestWav(8, []float64{0, 16384, -16384, 0, 8192, 8192, -8192, -8192})
+	wf := track.Waveform(2, 0, 0)
+
+	if got, want := wf.Max[0], []float64{0.5, 0.25}; !floatSliceEqual(got, want) {
+		t.Errorf("got max %f, wanted %f", got, want)
+	}
+	if got, want := wf.Min[0], []float64{-0.5, -0.25}; !floatSliceEqual(got, want) {
+		t.Errorf("got min %f, wanted %f", got, want)
+	}
+	if got, want := wf.RMS[0], []float64{0.5 / 1.4142135623730951, 0.25}; !floatSliceEqual(got, want) {
+		t.Errorf("got RMS %f, wanted %f", got, want)
+	}
+
+	var svg bytes.Buffer
+	if err := wf.WriteSVG(&svg, 10, true, -60); err != nil || strings.Count(svg.String(), "<polygon") != 2 {
+		t.Errorf("got SVG %q, error %v", svg.String(), err)
+	}
+}
@@ -5,4 +5,5 @@ go 1.15
 require (
 	github.com/mjibson/go-dsp v0.0.0-20180508042940-11479a337f12
 	github.com/spf13/cobra v1.1.3
+	gopkg.in/yaml.v2 v2.4.0
 )